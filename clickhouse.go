@@ -51,6 +51,8 @@ var (
 	ErrBindMixedParamsFormats    = errors.New("clickhouse [bind]: mixed named, numeric or positional parameters")
 	ErrAcquireConnNoAddress      = errors.New("clickhouse: no valid address supplied")
 	ErrServerUnexpectedData      = errors.New("code: 101, message: Unexpected packet Data received from client")
+	ErrUnsupportedBatchFormat    = errors.New("clickhouse: RowBinaryWithDefaults batches are only supported by the HTTP protocol")
+	ErrNoCurrentBlock            = errors.New("clickhouse: no current block, Next or NextBlock must be called first")
 )
 
 type OpError struct {
@@ -77,6 +79,23 @@ func (e *OpError) Error() string {
 	return fmt.Sprintf("clickhouse [%s]: %s", e.Op, e.Err)
 }
 
+// TypeCheckError is returned by a batch prepared with driver.WithStrictTypeCheck when an appended
+// value does not fit its column. Row is the position of the row in the batch block.
+type TypeCheckError struct {
+	Row        int
+	ColumnName string
+	ColumnType string
+	Err        error
+}
+
+func (e *TypeCheckError) Error() string {
+	return fmt.Sprintf("clickhouse [StrictTypeCheck]: row %d, column %s (%s): %s", e.Row, e.ColumnName, e.ColumnType, e.Err)
+}
+
+func (e *TypeCheckError) Unwrap() error {
+	return e.Err
+}
+
 func Open(opt *Options) (driver.Conn, error) {
 	if opt == nil {
 		opt = &Options{}
@@ -109,8 +128,10 @@ func (clickhouse) Contributors() []string {
 }
 
 func (ch *clickhouse) ServerVersion() (*driver.ServerVersion, error) {
+	base, cancelBase := ch.opt.withBaseContext(context.Background())
+	defer cancelBase()
 	var (
-		ctx, cancel = context.WithTimeout(context.Background(), ch.opt.DialTimeout)
+		ctx, cancel = context.WithTimeout(base, ch.opt.DialTimeout)
 		conn, err   = ch.acquire(ctx)
 	)
 	defer cancel()
@@ -122,26 +143,54 @@ func (ch *clickhouse) ServerVersion() (*driver.ServerVersion, error) {
 }
 
 func (ch *clickhouse) Query(ctx context.Context, query string, args ...any) (rows driver.Rows, err error) {
+	defer closeProgressChanOnError(ctx, &err)
+	ctx, cancel := ch.opt.withBaseContext(ctx)
+	// invalid options fail before a connection is acquired, see Context
+	if err := queryOptions(ctx).err; err != nil {
+		cancel()
+		return nil, err
+	}
 	conn, err := ch.acquire(ctx)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 	conn.debugf("[acquired] connection [%d]", conn.id)
-	return conn.query(ctx, ch.release, query, args...)
+	return conn.query(ctx, ch.releaseAndCancel(cancel), query, args...)
 }
 
 func (ch *clickhouse) QueryRow(ctx context.Context, query string, args ...any) (rows driver.Row) {
+	ctx, cancel := ch.opt.withBaseContext(ctx)
+	// invalid options fail before a connection is acquired, see Context
+	if err := queryOptions(ctx).err; err != nil {
+		cancel()
+		closeProgressChanOnError(ctx, &err)
+		return &row{
+			err: err,
+		}
+	}
 	conn, err := ch.acquire(ctx)
 	if err != nil {
+		cancel()
+		closeProgressChanOnError(ctx, &err)
 		return &row{
 			err: err,
 		}
 	}
 	conn.debugf("[acquired] connection [%d]", conn.id)
-	return conn.queryRow(ctx, ch.release, query, args...)
+	r := conn.queryRow(ctx, ch.releaseAndCancel(cancel), query, args...)
+	closeProgressChanOnError(ctx, &r.err)
+	return r
 }
 
-func (ch *clickhouse) Exec(ctx context.Context, query string, args ...any) error {
+func (ch *clickhouse) Exec(ctx context.Context, query string, args ...any) (err error) {
+	defer closeProgressChanOnError(ctx, &err)
+	ctx, cancel := ch.opt.withBaseContext(ctx)
+	defer cancel()
+	// invalid options fail before a connection is acquired, see Context
+	if err := queryOptions(ctx).err; err != nil {
+		return err
+	}
 	conn, err := ch.acquire(ctx)
 	if err != nil {
 		return err
@@ -154,15 +203,28 @@ func (ch *clickhouse) Exec(ctx context.Context, query string, args ...any) error
 	return nil
 }
 
-func (ch *clickhouse) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+func (ch *clickhouse) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (_ driver.Batch, err error) {
+	defer closeProgressChanOnError(ctx, &err)
+	ctx, cancel := ch.opt.withBaseContext(ctx)
+	// invalid options fail before a connection is acquired, see Context
+	if err := queryOptions(ctx).err; err != nil {
+		cancel()
+		return nil, err
+	}
 	conn, err := ch.acquire(ctx)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 	batch, err := conn.prepareBatch(ctx, query, getPrepareBatchOptions(opts...), ch.release, ch.acquire)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	if ch.opt.BaseContext != nil {
+		// the batch may release and acquire connections until it is sent, keep the context until then
+		return &baseContextBatch{Batch: batch, cancel: cancel}, nil
+	}
 	return batch, nil
 }
 
@@ -176,7 +238,14 @@ func getPrepareBatchOptions(opts ...driver.PrepareBatchOption) driver.PrepareBat
 	return options
 }
 
-func (ch *clickhouse) AsyncInsert(ctx context.Context, query string, wait bool, args ...any) error {
+func (ch *clickhouse) AsyncInsert(ctx context.Context, query string, wait bool, args ...any) (err error) {
+	defer closeProgressChanOnError(ctx, &err)
+	ctx, cancel := ch.opt.withBaseContext(ctx)
+	defer cancel()
+	// invalid options fail before a connection is acquired, see Context
+	if err := queryOptions(ctx).err; err != nil {
+		return err
+	}
 	conn, err := ch.acquire(ctx)
 	if err != nil {
 		return err
@@ -190,6 +259,8 @@ func (ch *clickhouse) AsyncInsert(ctx context.Context, query string, wait bool,
 }
 
 func (ch *clickhouse) Ping(ctx context.Context) (err error) {
+	ctx, cancel := ch.opt.withBaseContext(ctx)
+	defer cancel()
 	conn, err := ch.acquire(ctx)
 	if err != nil {
 		return err
@@ -277,7 +348,7 @@ func (ch *clickhouse) acquire(ctx context.Context) (conn *connect, err error) {
 		}
 		return nil, ErrAcquireConnTimeout
 	case conn := <-ch.idle:
-		if conn.isBad() {
+		if conn.isBad(ctx) {
 			conn.close()
 			if conn, err = ch.dial(ctx); err != nil {
 				select {
@@ -336,6 +407,14 @@ func (ch *clickhouse) closeIdleExpired() {
 	}
 }
 
+// releaseAndCancel releases the connection of a query and then the context of the query.
+func (ch *clickhouse) releaseAndCancel(cancel context.CancelFunc) func(*connect, error) {
+	return func(conn *connect, err error) {
+		ch.release(conn, err)
+		cancel()
+	}
+}
+
 func (ch *clickhouse) release(conn *connect, err error) {
 	if conn.released {
 		return
@@ -0,0 +1,73 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// ColumnMetadata describes a result column, see ColumnMetadataFromTable.
+type ColumnMetadata struct {
+	// DatabaseTypeName is the type of the result column.
+	DatabaseTypeName string
+	// Comment is the comment of the table column, empty if it has none.
+	Comment string
+	// FromTable reports whether the result column matches a column of the table by name and type. It is
+	// false for expressions, e.g. count() or a renamed column, which have no comment. An expression aliased
+	// to the name of a column of the same type, e.g. toString(s) AS s, cannot be told apart and matches.
+	FromTable bool
+}
+
+// ColumnMetadataFromTable returns the metadata of the result columns of rs, by name, with the comments of the
+// columns of table they match in system.columns. table may be qualified by its database, e.g. "db.events",
+// and is looked up in the current database otherwise.
+func ColumnMetadataFromTable(ctx context.Context, conn driver.Conn, rs driver.Rows, table string) (map[string]ColumnMetadata, error) {
+	database, table := "", strings.Trim(table, "`")
+	if i := strings.Index(table, "."); i != -1 {
+		database, table = strings.Trim(table[:i], "`"), strings.Trim(table[i+1:], "`")
+	}
+	// the query id or events of ctx, if any, belong to the query of rs which may still be running
+	comments, err := conn.Query(Context(ctx, internalQuery()), "SELECT name, type, comment FROM system.columns WHERE database = if(empty(?), currentDatabase(), ?) AND table = ?", database, database, table)
+	if err != nil {
+		return nil, err
+	}
+	defer comments.Close()
+	metadata := make(map[string]ColumnMetadata)
+	for _, column := range rs.ColumnTypes() {
+		metadata[column.Name()] = ColumnMetadata{
+			DatabaseTypeName: column.DatabaseTypeName(),
+		}
+	}
+	for comments.Next() {
+		var name, typ, comment string
+		if err := comments.Scan(&name, &typ, &comment); err != nil {
+			return nil, err
+		}
+		if column, ok := metadata[name]; ok && column.DatabaseTypeName == typ {
+			column.Comment, column.FromTable = comment, true
+			metadata[name] = column
+		}
+	}
+	if err := comments.Close(); err != nil {
+		return nil, err
+	}
+	return metadata, comments.Err()
+}
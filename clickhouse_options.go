@@ -143,6 +143,21 @@ type Options struct {
 	HttpUrlPath          string            // set additional URL path for HTTP requests
 	BlockBufferSize      uint8             // default 2 - can be overwritten on query
 	MaxCompressionBuffer int               // default 10485760 - measured in bytes  i.e. 10MiB
+	// AlignServerTimeouts sends, with each HTTP request, the server timeouts matching ReadTimeout, rounded up
+	// to whole seconds: receive_timeout and send_timeout are set to ReadTimeout. A timeout which is set in
+	// Settings or on the query is kept. Ignored by the native protocol.
+	AlignServerTimeouts bool
+	// HealthCheck, if set, runs after the lightweight liveness check of an idle connection when it is checked
+	// out of the pool, or reused by database/sql, and at most once per HealthCheckInterval on each connection.
+	// The connection is discarded if it returns an error, e.g. to reject replicas lagging behind with a query on
+	// system.replicas. It is bound by the context of the checkout and must complete within DialTimeout.
+	HealthCheck         func(ctx context.Context, conn HealthCheckConn) error
+	HealthCheckInterval time.Duration // default 1 minute - minimum time between two HealthCheck runs on a connection
+	// BaseContext, if set, returns the context every request of the driver derives from: it is used as is by
+	// internal requests made without a caller context (e.g. connection checks) and merged with the context of
+	// each call, including the timezone and version probes when dialing. A request is then cancelled when either
+	// context is done, and query options or values of the call context take precedence over the base context ones.
+	BaseContext func() context.Context
 
 	scheme      string
 	ReadTimeout time.Duration
@@ -224,6 +239,16 @@ func (o *Options) fromDSN(in string) error {
 				return fmt.Errorf("clickhouse [dsn parse]: dial timeout: %s", err)
 			}
 			o.DialTimeout = duration
+		case "align_server_timeouts":
+			alignParam := params.Get(v)
+			if alignParam == "" {
+				o.AlignServerTimeouts = true
+			} else {
+				o.AlignServerTimeouts, err = strconv.ParseBool(alignParam)
+				if err != nil {
+					return fmt.Errorf("clickhouse [dsn parse]:align server timeouts: %s", err)
+				}
+			}
 		case "block_buffer_size":
 			if blockBufferSize, err := strconv.ParseUint(params.Get(v), 10, 8); err == nil {
 				if blockBufferSize <= 0 {
@@ -337,6 +362,59 @@ func (o *Options) fromDSN(in string) error {
 	return nil
 }
 
+// withBaseContext merges ctx with the context returned by BaseContext. The returned context is done when either
+// of them is done and must be released with cancel once the request is over.
+func (o *Options) withBaseContext(ctx context.Context) (_ context.Context, cancel context.CancelFunc) {
+	if o.BaseContext == nil {
+		return ctx, func() {}
+	}
+	base := o.BaseContext()
+	if base == nil {
+		return ctx, func() {}
+	}
+	merged, cancelMerged := context.WithCancelCause(baseValueContext{Context: ctx, base: base})
+	stop := context.AfterFunc(base, func() {
+		cancelMerged(context.Cause(base))
+	})
+	cancelDeadline := context.CancelFunc(func() {})
+	if deadline, ok := base.Deadline(); ok {
+		merged, cancelDeadline = context.WithDeadline(merged, deadline)
+	}
+	return merged, func() {
+		stop()
+		cancelDeadline()
+		cancelMerged(context.Canceled)
+	}
+}
+
+// baseValueContext looks up values in its context first and then in base.
+type baseValueContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c baseValueContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.base.Value(key)
+}
+
+// serverTimeoutSettings returns the server timeouts matching ReadTimeout, see AlignServerTimeouts.
+func (o *Options) serverTimeoutSettings() Settings {
+	if !o.AlignServerTimeouts || o.ReadTimeout <= 0 {
+		return nil
+	}
+	settings := make(Settings, 2)
+	for _, key := range []string{"receive_timeout", "send_timeout"} {
+		if _, ok := o.Settings[key]; ok {
+			continue
+		}
+		settings[key] = int((o.ReadTimeout + time.Second - 1) / time.Second)
+	}
+	return settings
+}
+
 // receive copy of Options, so we don't modify original - so its reusable
 func (o Options) setDefaults() *Options {
 	if len(o.Auth.Username) == 0 {
@@ -357,6 +435,9 @@ func (o Options) setDefaults() *Options {
 	if o.ConnMaxLifetime == 0 {
 		o.ConnMaxLifetime = time.Hour
 	}
+	if o.HealthCheckInterval == 0 {
+		o.HealthCheckInterval = time.Minute
+	}
 	if o.BlockBufferSize <= 0 {
 		o.BlockBufferSize = 2
 	}
@@ -18,11 +18,15 @@
 package clickhouse
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestParseDSN does not implement all use cases yet
@@ -467,6 +471,19 @@ func TestParseDSN(t *testing.T) {
 			},
 			"",
 		},
+		{
+			"http protocol with aligned server timeouts",
+			"http://127.0.0.1/?align_server_timeouts&read_timeout=90s",
+			&Options{
+				Protocol:            HTTP,
+				AlignServerTimeouts: true,
+				ReadTimeout:         90 * time.Second,
+				Addr:                []string{"127.0.0.1"},
+				Settings:            Settings{},
+				scheme:              "http",
+			},
+			"",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -484,3 +501,104 @@ func TestParseDSN(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionsServerTimeoutSettings(t *testing.T) {
+	opt := Options{ReadTimeout: 1500 * time.Millisecond}
+	assert.Nil(t, opt.serverTimeoutSettings())
+
+	opt.AlignServerTimeouts = true
+	assert.Equal(t, Settings{
+		"receive_timeout": 2,
+		"send_timeout":    2,
+	}, opt.setDefaults().serverTimeoutSettings())
+
+	// explicit settings are kept
+	opt.Settings = Settings{"receive_timeout": 10}
+	assert.Equal(t, Settings{
+		"send_timeout": 2,
+	}, opt.setDefaults().serverTimeoutSettings())
+
+	// the timeouts are sent with each request, the settings of the query take precedence
+	opt.Settings = nil
+	h := &httpConnect{url: &url.URL{Scheme: "http", Host: "localhost:8123"}, opt: opt.setDefaults()}
+	opts := queryOptions(Context(context.Background(), WithSettings(Settings{"send_timeout": 5})))
+	req, err := h.prepareRequest(context.Background(), "SELECT 1", &opts, map[string]string{})
+	require.NoError(t, err)
+	query := req.URL.Query()
+	assert.Equal(t, "5", query.Get("send_timeout"))
+	assert.Equal(t, "2", query.Get("receive_timeout"))
+	assert.False(t, query.Has("http_connection_timeout"))
+}
+
+func TestOptionsWithBaseContext(t *testing.T) {
+	type key string
+	t.Run("no base context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), key("a"), "call")
+		merged, cancel := (&Options{}).withBaseContext(ctx)
+		defer cancel()
+		assert.Equal(t, ctx, merged)
+	})
+
+	t.Run("values", func(t *testing.T) {
+		base := context.WithValue(context.WithValue(context.Background(), key("a"), "base"), key("b"), "base")
+		opt := &Options{BaseContext: func() context.Context { return base }}
+		merged, cancel := opt.withBaseContext(context.WithValue(context.Background(), key("a"), "call"))
+		defer cancel()
+		assert.Equal(t, "call", merged.Value(key("a")))
+		assert.Equal(t, "base", merged.Value(key("b")))
+
+		// query options of the call take precedence
+		base = Context(context.Background(), WithQueryID("base"))
+		merged, cancel = opt.withBaseContext(Context(context.Background(), WithQueryID("call")))
+		defer cancel()
+		assert.Equal(t, "call", queryOptions(merged).queryID)
+		merged, cancel = opt.withBaseContext(context.Background())
+		defer cancel()
+		assert.Equal(t, "base", queryOptions(merged).queryID)
+	})
+
+	t.Run("base cancellation", func(t *testing.T) {
+		base, cancelBase := context.WithCancelCause(context.Background())
+		opt := &Options{BaseContext: func() context.Context { return base }}
+		merged, cancel := opt.withBaseContext(context.Background())
+		defer cancel()
+		shutdown := errors.New("shutdown")
+		cancelBase(shutdown)
+		select {
+		case <-merged.Done():
+		case <-time.After(time.Second):
+			t.Fatal("merged context is not cancelled with the base context")
+		}
+		assert.ErrorIs(t, merged.Err(), context.Canceled)
+		assert.ErrorIs(t, context.Cause(merged), shutdown)
+	})
+
+	t.Run("deadlines", func(t *testing.T) {
+		base, cancelBase := context.WithTimeout(context.Background(), time.Hour)
+		defer cancelBase()
+		opt := &Options{BaseContext: func() context.Context { return base }}
+
+		call, cancelCall := context.WithTimeout(context.Background(), time.Minute)
+		defer cancelCall()
+		merged, cancel := opt.withBaseContext(call)
+		defer cancel()
+		deadline, ok := merged.Deadline()
+		assert.True(t, ok)
+		callDeadline, _ := call.Deadline()
+		assert.Equal(t, callDeadline, deadline)
+
+		merged, cancel = opt.withBaseContext(context.Background())
+		defer cancel()
+		deadline, ok = merged.Deadline()
+		assert.True(t, ok)
+		baseDeadline, _ := base.Deadline()
+		assert.Equal(t, baseDeadline, deadline)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		opt := &Options{BaseContext: context.Background}
+		merged, cancel := opt.withBaseContext(context.Background())
+		cancel()
+		assert.ErrorIs(t, merged.Err(), context.Canceled)
+	})
+}
@@ -0,0 +1,156 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+)
+
+// NextBlock advances rs to the first row of the next block of the result, skipping the remaining
+// rows of the current one. It is the block-wise counterpart of Next, to be used with BlockColumn:
+//
+//	for clickhouse.NextBlock(rows) {
+//		values, err := clickhouse.BlockColumn[float64](rows, "value")
+//		...
+//	}
+func NextBlock(rs driver.Rows) bool {
+	r, ok := rs.(*rows)
+	if !ok {
+		return false
+	}
+	if r.block != nil && r.row > 0 {
+		r.row = r.block.Rows()
+	}
+	return r.Next()
+}
+
+// BlockColumn returns all the values of a numeric column, by index or name, in the block holding the
+// current row of rs, e.g. BlockColumn[float64](rows, "value") for a Float64 column. T must match the
+// column type exactly, Nullable columns are not supported.
+//
+// The values are not copied: the slice points into the decoded block and is only valid until rs
+// moves to the next block or is closed, and it must not be modified. Use BlockColumnCopy to keep the
+// values beyond that.
+func BlockColumn[T column.Numeric, C int | string](rs driver.Rows, col C) ([]T, error) {
+	c, err := currentBlockColumn(rs, col, "BlockColumn")
+	if err != nil {
+		return nil, err
+	}
+	values, ok := column.NumericValues[T](c)
+	if !ok {
+		return nil, blockColumnConverterError[T]("BlockColumn", c, c)
+	}
+	return values, nil
+}
+
+// BlockColumnCopy is like BlockColumn, but returns a copy of the values which remains valid
+// once rs moves to the next block.
+func BlockColumnCopy[T column.Numeric, C int | string](rs driver.Rows, col C) ([]T, error) {
+	values, err := BlockColumn[T](rs, col)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(values), nil
+}
+
+// BlockMapColumn returns the entries of a Map column, by index or name, in the block holding the current
+// row of rs as flattened keys and values, e.g. BlockMapColumn[string, uint64](rows, "attributes") for a
+// Map(String, UInt64) column. offsets holds the end offset of every row: the entries of row i of the block
+// are keys[offsets[i-1]:offsets[i]] and values[offsets[i-1]:offsets[i]], starting at 0 for the first row.
+//
+// Numeric keys, numeric values and offsets are not copied and follow the lifetime rules of BlockColumn,
+// String keys and values are copied. K and V must match the key and value types exactly, Nullable values
+// are not supported.
+func BlockMapColumn[K, V column.Scalar, C int | string](rs driver.Rows, col C) (keys []K, values []V, offsets []int64, err error) {
+	c, err := currentBlockColumn(rs, col, "BlockMapColumn")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	m, ok := c.(*column.Map)
+	if !ok {
+		return nil, nil, nil, &OpError{
+			Op:         "BlockMapColumn",
+			ColumnName: c.Name(),
+			Err:        fmt.Errorf("column type %s is not a Map", c.Type()),
+		}
+	}
+	if keys, ok = column.ScalarValues[K](m.Keys()); !ok {
+		return nil, nil, nil, blockColumnConverterError[K]("BlockMapColumn", c, m.Keys())
+	}
+	if values, ok = column.ScalarValues[V](m.Values()); !ok {
+		return nil, nil, nil, blockColumnConverterError[V]("BlockMapColumn", c, m.Values())
+	}
+	return keys, values, m.Offsets(), nil
+}
+
+func currentBlockColumn[C int | string](rs driver.Rows, col C, op string) (column.Interface, error) {
+	r, ok := rs.(*rows)
+	if !ok {
+		return nil, &OpError{
+			Op:  op,
+			Err: fmt.Errorf("unsupported rows type %T", rs),
+		}
+	}
+	if r.block == nil || r.row == 0 {
+		return nil, ErrNoCurrentBlock
+	}
+	return blockColumn(r.block, col, op)
+}
+
+func blockColumnConverterError[T any](op string, col, values column.Interface) error {
+	var zero T
+	return &OpError{
+		Op:         op,
+		ColumnName: col.Name(),
+		Err: &column.ColumnConverterError{
+			Op:   op,
+			To:   fmt.Sprintf("[]%T", zero),
+			From: string(values.Type()),
+		},
+	}
+}
+
+func blockColumn[C int | string](block *proto.Block, col C, op string) (column.Interface, error) {
+	switch col := any(col).(type) {
+	case int:
+		if col < 0 || col >= len(block.Columns) {
+			return nil, &OpError{
+				Op:  op,
+				Err: fmt.Errorf("invalid column index %d", col),
+			}
+		}
+		return block.Columns[col], nil
+	case string:
+		for i, name := range block.ColumnsNames() {
+			if name == col {
+				return block.Columns[i], nil
+			}
+		}
+		return nil, &OpError{
+			Op:         op,
+			ColumnName: col,
+			Err:        fmt.Errorf("column %s not found", col),
+		}
+	}
+	return nil, nil
+}
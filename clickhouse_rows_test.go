@@ -1,8 +1,10 @@
 package clickhouse
 
 import (
+	chproto "github.com/ClickHouse/ch-go/proto"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"strconv"
 	"testing"
 )
@@ -114,3 +116,116 @@ func TestReadWithEmptyBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockColumn(t *testing.T) {
+	newBlock := func(values ...float64) *proto.Block {
+		block := &proto.Block{}
+		require.NoError(t, block.AddColumn("id", "UInt64"))
+		require.NoError(t, block.AddColumn("value", "Float64"))
+		for i, v := range values {
+			require.NoError(t, block.Append(uint64(i), v))
+		}
+		return block
+	}
+	blockChan := make(chan *proto.Block, 3)
+	blockChan <- newBlock()
+	blockChan <- newBlock(3, 4, 5)
+	blockChan <- newBlock(6)
+	close(blockChan)
+	r := &rows{
+		block:  newBlock(1, 2),
+		stream: blockChan,
+	}
+
+	_, err := BlockColumn[float64](r, "value")
+	require.ErrorIs(t, err, ErrNoCurrentBlock)
+
+	var (
+		values [][]float64
+		copies [][]float64
+	)
+	for NextBlock(r) {
+		v, err := BlockColumn[float64](r, "value")
+		require.NoError(t, err)
+		c, err := BlockColumnCopy[float64](r, 1)
+		require.NoError(t, err)
+		values, copies = append(values, v), append(copies, c)
+
+		_, err = BlockColumn[float32](r, "value")
+		assert.EqualError(t, err, "clickhouse [BlockColumn]: (value) converting Float64 to []float32 is unsupported")
+		_, err = BlockColumn[float64](r, "missing")
+		assert.Error(t, err)
+		_, err = BlockColumn[float64](r, 2)
+		assert.Error(t, err)
+	}
+	require.NoError(t, r.Err())
+	assert.Equal(t, [][]float64{{1, 2}, {3, 4, 5}, {6}}, values)
+	assert.Equal(t, values, copies)
+	copies[0][0] = 10
+	assert.Equal(t, float64(1), values[0][0])
+
+	// NextBlock skips the remaining rows of the block
+	blockChan = make(chan *proto.Block, 1)
+	blockChan <- newBlock(3, 4)
+	close(blockChan)
+	r = &rows{
+		block:  newBlock(1, 2),
+		stream: blockChan,
+	}
+	var (
+		id    uint64
+		value float64
+	)
+	require.True(t, r.Next())
+	require.NoError(t, r.Scan(&id, &value))
+	assert.Equal(t, float64(1), value)
+	require.True(t, NextBlock(r))
+	require.NoError(t, r.Scan(&id, &value))
+	assert.Equal(t, float64(3), value)
+	ids, err := BlockColumn[uint64](r, "id")
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0, 1}, ids)
+	assert.False(t, NextBlock(r))
+}
+
+func TestBlockMapColumn(t *testing.T) {
+	block := &proto.Block{}
+	require.NoError(t, block.AddColumn("id", "UInt64"))
+	require.NoError(t, block.AddColumn("counters", "Map(String, UInt64)"))
+	require.NoError(t, block.AddColumn("labels", "Map(LowCardinality(String), String)"))
+	rowsData := []struct {
+		counters map[string]uint64
+		labels   map[string]string
+	}{
+		{counters: map[string]uint64{"a": 1}, labels: map[string]string{"env": "prod"}},
+		{counters: map[string]uint64{}, labels: map[string]string{}},
+		{counters: map[string]uint64{"b": 2}, labels: map[string]string{"env": "dev"}},
+	}
+	for i, row := range rowsData {
+		require.NoError(t, block.Append(uint64(i), row.counters, row.labels))
+	}
+	// decode the block as received from the server
+	var buffer chproto.Buffer
+	require.NoError(t, block.Encode(&buffer, 0))
+	decoded := &proto.Block{}
+	require.NoError(t, decoded.Decode(chproto.NewReader(&buffer), 0))
+	r := &rows{block: decoded}
+	require.True(t, NextBlock(r))
+
+	keys, values, offsets, err := BlockMapColumn[string, uint64](r, "counters")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, keys)
+	assert.Equal(t, []uint64{1, 2}, values)
+	assert.Equal(t, []int64{1, 1, 2}, offsets)
+
+	labelKeys, labelValues, offsets, err := BlockMapColumn[string, string](r, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env", "env"}, labelKeys)
+	assert.Equal(t, []string{"prod", "dev"}, labelValues)
+	assert.Equal(t, []int64{1, 1, 2}, offsets)
+
+	_, _, _, err = BlockMapColumn[string, int64](r, "counters")
+	assert.EqualError(t, err, "clickhouse [BlockMapColumn]: (counters) converting UInt64 to []int64 is unsupported")
+	_, _, _, err = BlockMapColumn[string, string](r, "id")
+	assert.Error(t, err)
+}
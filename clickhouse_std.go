@@ -81,6 +81,9 @@ func (o *stdConnOpener) Connect(ctx context.Context) (_ driver.Conn, err error)
 		return nil, ErrAcquireConnNoAddress
 	}
 
+	ctx, cancel := o.opt.withBaseContext(ctx)
+	defer cancel()
+
 	for i := range o.opt.Addr {
 		var num int
 		switch o.opt.ConnOpenStrategy {
@@ -99,6 +102,7 @@ func (o *stdConnOpener) Connect(ctx context.Context) (_ driver.Conn, err error)
 				}
 			}
 			return &stdDriver{
+				opt:    o.opt,
 				conn:   conn,
 				debugf: debugf,
 			}, nil
@@ -183,7 +187,7 @@ func OpenDB(opt *Options) *sql.DB {
 }
 
 type stdConnect interface {
-	isBad() bool
+	isBad(ctx context.Context) bool
 	close() error
 	query(ctx context.Context, release func(*connect, error), query string, args ...any) (*rows, error)
 	exec(ctx context.Context, query string, args ...any) error
@@ -193,8 +197,9 @@ type stdConnect interface {
 }
 
 type stdDriver struct {
+	opt    *Options
 	conn   stdConnect
-	commit func() error
+	batch  *stdBatch // prepared batch sent on Commit
 	debugf func(format string, v ...any)
 }
 
@@ -222,7 +227,7 @@ func (std *stdDriver) Open(dsn string) (_ driver.Conn, err error) {
 var _ driver.Driver = (*stdDriver)(nil)
 
 func (std *stdDriver) ResetSession(ctx context.Context) error {
-	if std.conn.isBad() {
+	if std.conn.isBad(ctx) {
 		std.debugf("Resetting session because connection is bad")
 		return driver.ErrBadConn
 	}
@@ -231,7 +236,11 @@ func (std *stdDriver) ResetSession(ctx context.Context) error {
 
 var _ driver.SessionResetter = (*stdDriver)(nil)
 
-func (std *stdDriver) Ping(ctx context.Context) error { return std.conn.ping(ctx) }
+func (std *stdDriver) Ping(ctx context.Context) error {
+	ctx, cancel := std.opt.withBaseContext(ctx)
+	defer cancel()
+	return std.conn.ping(ctx)
+}
 
 var _ driver.Pinger = (*stdDriver)(nil)
 
@@ -241,14 +250,13 @@ func (std *stdDriver) BeginTx(ctx context.Context, opts driver.TxOptions) (drive
 }
 
 func (std *stdDriver) Commit() error {
-	if std.commit == nil {
+	if std.batch == nil {
 		return nil
 	}
-	defer func() {
-		std.commit = nil
-	}()
+	batch := std.batch
+	std.batch = nil
 
-	if err := std.commit(); err != nil {
+	if err := batch.send(); err != nil {
 		if isConnBrokenError(err) {
 			std.debugf("Commit got EOF error: resetting connection")
 			return driver.ErrBadConn
@@ -260,7 +268,10 @@ func (std *stdDriver) Commit() error {
 }
 
 func (std *stdDriver) Rollback() error {
-	std.commit = nil
+	if std.batch != nil {
+		std.batch.cancel()
+		std.batch = nil
+	}
 	std.conn.close()
 	return nil
 }
@@ -271,7 +282,10 @@ func (std *stdDriver) CheckNamedValue(nv *driver.NamedValue) error { return nil
 
 var _ driver.NamedValueChecker = (*stdDriver)(nil)
 
-func (std *stdDriver) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+func (std *stdDriver) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (_ driver.Result, err error) {
+	defer closeProgressChanOnError(ctx, &err)
+	ctx, cancel := std.opt.withBaseContext(ctx)
+	defer cancel()
 	if options := queryOptions(ctx); options.async.ok {
 		return driver.RowsAffected(0), std.conn.asyncInsert(ctx, query, options.async.wait, rebind(args)...)
 	}
@@ -286,18 +300,23 @@ func (std *stdDriver) ExecContext(ctx context.Context, query string, args []driv
 	return driver.RowsAffected(0), nil
 }
 
-func (std *stdDriver) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+func (std *stdDriver) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (_ driver.Rows, err error) {
+	defer closeProgressChanOnError(ctx, &err)
+	ctx, cancel := std.opt.withBaseContext(ctx)
 	r, err := std.conn.query(ctx, func(*connect, error) {}, query, rebind(args)...)
 	if isConnBrokenError(err) {
+		cancel()
 		std.debugf("QueryContext got a fatal error, resetting connection: %v\n", err)
 		return nil, driver.ErrBadConn
 	}
 	if err != nil {
+		cancel()
 		std.debugf("QueryContext error: %v\n", err)
 		return nil, err
 	}
 	return &stdRows{
 		rows:   r,
+		cancel: cancel,
 		debugf: std.debugf,
 	}, nil
 }
@@ -306,9 +325,15 @@ func (std *stdDriver) Prepare(query string) (driver.Stmt, error) {
 	return std.PrepareContext(context.Background(), query)
 }
 
-func (std *stdDriver) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	batch, err := std.conn.prepareBatch(ctx, query, ldriver.PrepareBatchOptions{}, func(*connect, error) {}, func(context.Context) (*connect, error) { return nil, nil })
+func (std *stdDriver) PrepareContext(ctx context.Context, query string) (_ driver.Stmt, err error) {
+	defer closeProgressChanOnError(ctx, &err)
+	ctx, cancel := std.opt.withBaseContext(ctx)
+	options := ldriver.PrepareBatchOptions{
+		RowBinaryWithDefaults: queryOptions(ctx).stdRowBinaryWithDefaults,
+	}
+	batch, err := std.conn.prepareBatch(ctx, query, options, func(*connect, error) {}, func(context.Context) (*connect, error) { return nil, nil })
 	if err != nil {
+		cancel()
 		if isConnBrokenError(err) {
 			std.debugf("PrepareContext got a fatal error, resetting connection: %v\n", err)
 			return nil, driver.ErrBadConn
@@ -316,11 +341,17 @@ func (std *stdDriver) PrepareContext(ctx context.Context, query string) (driver.
 		std.debugf("PrepareContext error: %v\n", err)
 		return nil, err
 	}
-	std.commit = batch.Send
-	return &stdBatch{
+	if std.batch != nil {
+		// the batch prepared before can no longer be committed
+		std.batch.cancel()
+	}
+	std.batch = &stdBatch{
+		std:    std,
 		batch:  batch,
+		cancel: cancel,
 		debugf: std.debugf,
-	}, nil
+	}
+	return std.batch, nil
 }
 
 func (std *stdDriver) Close() error {
@@ -336,10 +367,17 @@ func (std *stdDriver) Close() error {
 }
 
 type stdBatch struct {
+	std    *stdDriver
 	batch  ldriver.Batch
+	cancel context.CancelFunc // releases the context merged with Options.BaseContext
 	debugf func(format string, v ...any)
 }
 
+func (s *stdBatch) send() error {
+	defer s.cancel()
+	return s.batch.Send()
+}
+
 func (s *stdBatch) NumInput() int { return -1 }
 func (s *stdBatch) Exec(args []driver.Value) (driver.Result, error) {
 	values := make([]any, 0, len(args))
@@ -368,10 +406,17 @@ func (s *stdBatch) Query(args []driver.Value) (driver.Rows, error) {
 	return nil, errors.New("only Exec method supported in batch mode")
 }
 
-func (s *stdBatch) Close() error { return nil }
+func (s *stdBatch) Close() error {
+	// a batch waiting for Commit keeps its context until it is sent or rolled back
+	if s.std.batch != s {
+		s.cancel()
+	}
+	return nil
+}
 
 type stdRows struct {
 	rows   *rows
+	cancel context.CancelFunc
 	debugf func(format string, v ...any)
 }
 
@@ -465,6 +510,7 @@ var _ driver.RowsNextResultSet = (*stdRows)(nil)
 
 func (r *stdRows) Close() error {
 	err := r.rows.Close()
+	r.cancel()
 	if err != nil {
 		r.debugf("Rows Close error: %v\n", err)
 	}
@@ -122,6 +122,7 @@ type connect struct {
 	structMap            *structMap
 	compression          CompressionMethod
 	connectedAt          time.Time
+	healthCheckedAt      time.Time
 	compressor           *compress.Writer
 	readTimeout          time.Duration
 	blockBufferSize      uint8
@@ -154,7 +155,7 @@ func (c *connect) settings(querySettings Settings) []proto.Setting {
 	return settings
 }
 
-func (c *connect) isBad() bool {
+func (c *connect) isBad(ctx context.Context) bool {
 	switch {
 	case c.closed:
 		return true
@@ -167,6 +168,9 @@ func (c *connect) isBad() bool {
 	if err := c.connCheck(); err != nil {
 		return true
 	}
+	if c.opt.HealthCheck != nil {
+		return healthCheck(ctx, c.opt, c, &c.healthCheckedAt) != nil
+	}
 	return false
 }
 
@@ -24,6 +24,7 @@ import (
 
 func (c *connect) asyncInsert(ctx context.Context, query string, wait bool, args ...any) error {
 	options := queryOptions(ctx)
+	defer options.events.progressChan.close()
 	{
 		options.settings["async_insert"] = 1
 		options.settings["wait_for_async_insert"] = 0
@@ -41,8 +42,20 @@ func (c *connect) asyncInsert(ctx context.Context, query string, wait bool, args
 		}
 	}
 
-	if err := c.sendQuery(query, &options); err != nil {
+	onProcess := options.onProcess()
+	var ack *InsertAck
+	if options.events.insertAck != nil && options.acknowledgesInsert(c.opt.Settings, false) {
+		ack = &InsertAck{}
+		trackInsertAck(onProcess, ack)
+	}
+	if err := c.sendQuery(ctx, query, &options); err != nil {
+		return err
+	}
+	if err := c.process(ctx, onProcess); err != nil {
 		return err
 	}
-	return c.process(ctx, options.onProcess())
+	if ack != nil {
+		options.events.insertAck(ack)
+	}
+	return nil
 }
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"time"
@@ -41,6 +42,10 @@ func (c *connect) prepareBatch(ctx context.Context, query string, opts driver.Pr
 	//		fmt.Printf("panic occurred on %d:\n", c.num)
 	//	}
 	//}()
+	if opts.RowBinaryWithDefaults {
+		release(c, nil)
+		return nil, ErrUnsupportedBatchFormat
+	}
 	query = splitInsertRe.Split(query, -1)[0]
 	colMatch := columnMatch.FindStringSubmatch(query)
 	var columns []string
@@ -52,6 +57,12 @@ func (c *connect) prepareBatch(ctx context.Context, query string, opts driver.Pr
 			columns[i] = strings.Trim(strings.Trim(strings.TrimSpace(columns[i]), "\""), "`")
 		}
 	}
+	if opts.OmitDefaultColumns && len(columns) != 0 {
+		if err := c.checkOmittedColumns(ctx, query, columns); err != nil {
+			release(c, err)
+			return nil, err
+		}
+	}
 	if !strings.HasSuffix(strings.TrimSpace(strings.ToUpper(query)), "VALUES") {
 		query += " VALUES"
 	}
@@ -60,14 +71,17 @@ func (c *connect) prepareBatch(ctx context.Context, query string, opts driver.Pr
 		c.conn.SetDeadline(deadline)
 		defer c.conn.SetDeadline(time.Time{})
 	}
-	if err := c.sendQuery(query, &options); err != nil {
+	if err := c.sendQuery(ctx, query, &options); err != nil {
 		release(c, err)
 		return nil, err
 	}
-	var (
-		onProcess  = options.onProcess()
-		block, err = c.firstBlock(ctx, onProcess)
-	)
+	onProcess := options.onProcess()
+	var ack *InsertAck
+	if options.events.insertAck != nil && options.acknowledgesInsert(c.opt.Settings, false) {
+		ack = &InsertAck{}
+		trackInsertAck(onProcess, ack)
+	}
+	block, err := c.firstBlock(ctx, onProcess)
 	if err != nil {
 		release(c, err)
 		return nil, err
@@ -78,14 +92,18 @@ func (c *connect) prepareBatch(ctx context.Context, query string, opts driver.Pr
 	}
 
 	b := &batch{
-		ctx:         ctx,
-		query:       query,
-		conn:        c,
-		block:       block,
-		released:    false,
-		connRelease: release,
-		connAcquire: acquire,
-		onProcess:   onProcess,
+		ctx:          ctx,
+		query:        query,
+		conn:         c,
+		block:        block,
+		released:     false,
+		connRelease:  release,
+		connAcquire:  acquire,
+		onProcess:    onProcess,
+		progressChan: options.events.progressChan,
+		strict:       opts.StrictTypeCheck,
+		ack:          ack,
+		onAck:        options.events.insertAck,
 	}
 
 	if opts.ReleaseConnection {
@@ -96,16 +114,20 @@ func (c *connect) prepareBatch(ctx context.Context, query string, opts driver.Pr
 }
 
 type batch struct {
-	err         error
-	ctx         context.Context
-	query       string
-	conn        *connect
-	sent        bool // sent signalize that batch is send to ClickHouse.
-	released    bool // released signalize that conn was returned to pool and can't be used.
-	block       *proto.Block
-	connRelease func(*connect, error)
-	connAcquire func(context.Context) (*connect, error)
-	onProcess   *onProcess
+	err          error
+	ctx          context.Context
+	query        string
+	conn         *connect
+	sent         bool // sent signalize that batch is send to ClickHouse.
+	released     bool // released signalize that conn was returned to pool and can't be used.
+	block        *proto.Block
+	connRelease  func(*connect, error)
+	connAcquire  func(context.Context) (*connect, error)
+	onProcess    *onProcess
+	progressChan *progressChan
+	strict       bool
+	ack          *InsertAck // rows written by the server, nil unless acknowledged, see WithInsertAck
+	onAck        func(*InsertAck)
 }
 
 func (b *batch) release(err error) {
@@ -118,6 +140,7 @@ func (b *batch) release(err error) {
 func (b *batch) Abort() error {
 	defer func() {
 		b.sent = true
+		b.progressChan.close()
 		b.release(os.ErrProcessDone)
 	}()
 	if b.sent {
@@ -140,6 +163,11 @@ func (b *batch) Append(v ...any) error {
 		}
 	}
 
+	if b.strict {
+		if err := checkRowTypes(b.block, v); err != nil {
+			return err
+		}
+	}
 	if err := b.block.Append(v...); err != nil {
 		b.err = errors.Wrap(ErrBatchInvalid, err.Error())
 		b.release(err)
@@ -209,6 +237,7 @@ func (b *batch) Column(idx int) driver.BatchColumn {
 	return &batchColumn{
 		batch:  b,
 		column: b.block.Columns[idx],
+		strict: b.strict,
 		release: func(err error) {
 			b.err = err
 			b.release(err)
@@ -228,6 +257,7 @@ func (b *batch) Send() (err error) {
 	defer func() {
 		stopCW()
 		b.sent = true
+		b.progressChan.close()
 		b.release(err)
 	}()
 	if b.err != nil {
@@ -252,6 +282,9 @@ func (b *batch) Send() (err error) {
 	if err = b.closeQuery(); err != nil {
 		return err
 	}
+	if b.ack != nil {
+		b.onAck(b.ack)
+	}
 	return nil
 }
 
@@ -271,7 +304,7 @@ func (b *batch) resetConnection() (err error) {
 		defer b.conn.conn.SetDeadline(time.Time{})
 	}
 
-	if err = b.conn.sendQuery(b.query, &options); err != nil {
+	if err = b.conn.sendQuery(b.ctx, b.query, &options); err != nil {
 		b.release(err)
 		return err
 	}
@@ -325,6 +358,7 @@ type batchColumn struct {
 	err     error
 	batch   driver.Batch
 	column  column.Interface
+	strict  bool
 	release func(error)
 }
 
@@ -335,6 +369,15 @@ func (b *batchColumn) Append(v any) (err error) {
 	if b.batch.IsSent() {
 		return ErrBatchAlreadySent
 	}
+	if b.strict {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				if err := checkType(b.column, b.column.Rows()+i, rv.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	if _, err = b.column.Append(v); err != nil {
 		b.release(err)
 		return err
@@ -349,6 +392,11 @@ func (b *batchColumn) AppendRow(v any) (err error) {
 	if b.batch.IsSent() {
 		return ErrBatchAlreadySent
 	}
+	if b.strict {
+		if err := checkType(b.column, b.column.Rows(), v); err != nil {
+			return err
+		}
+	}
 	if err = b.column.AppendRow(v); err != nil {
 		b.release(err)
 		return err
@@ -356,6 +404,105 @@ func (b *batchColumn) AppendRow(v any) (err error) {
 	return nil
 }
 
+// baseContextBatch releases the context merged with Options.BaseContext once the batch is sent or aborted,
+// whether it succeeds or not: a batch cannot be sent again after Send.
+type baseContextBatch struct {
+	driver.Batch
+	cancel context.CancelFunc
+}
+
+func (b *baseContextBatch) Abort() error {
+	defer b.cancel()
+	return b.Batch.Abort()
+}
+
+func (b *baseContextBatch) Send() error {
+	defer b.cancel()
+	return b.Batch.Send()
+}
+
+// checkOmittedColumns describes the table of an INSERT query and checks that the columns missing from its
+// column list have a default expression, see driver.WithOmitDefaultColumns.
+func (c *connect) checkOmittedColumns(ctx context.Context, query string, columns []string) error {
+	matches := httpInsertRe.FindStringSubmatch(strings.TrimSpace(query))
+	if len(matches) < 2 {
+		return errors.New("cannot get table name from query")
+	}
+	tableName := matches[1]
+	// the connection is released by prepareBatch on error
+	r, err := c.query(Context(ctx, ignoreProgressChan()), func(*connect, error) {}, "DESCRIBE TABLE "+tableName)
+	if err != nil {
+		return err
+	}
+	var (
+		colNames     []string
+		defaultTypes = make(map[string]string)
+	)
+	for r.Next() {
+		var (
+			colName      string
+			default_type string
+			ignore       string
+		)
+		if err = r.Scan(&colName, &ignore, &default_type, &ignore, &ignore, &ignore, &ignore); err != nil {
+			r.Close()
+			return err
+		}
+		colNames = append(colNames, colName)
+		defaultTypes[colName] = default_type
+	}
+	if err = r.Close(); err != nil {
+		return err
+	}
+	return checkOmittedColumnDefaults(tableName, columns, colNames, defaultTypes)
+}
+
+// checkOmittedColumnDefaults returns an error for the first column of the table, in colNames, which is
+// not in columns and has no default expression. defaultTypes holds the default_type of DESCRIBE TABLE.
+func checkOmittedColumnDefaults(tableName string, columns, colNames []string, defaultTypes map[string]string) error {
+	requested := make(map[string]struct{}, len(columns))
+	for _, name := range columns {
+		requested[name] = struct{}{}
+	}
+	for _, name := range colNames {
+		if _, ok := requested[name]; ok || defaultTypes[name] != "" {
+			continue
+		}
+		return &OpError{
+			Op:         "PrepareBatch",
+			ColumnName: name,
+			Err:        fmt.Errorf("column %s of the table %s is omitted from the INSERT but has no default expression", name, tableName),
+		}
+	}
+	return nil
+}
+
+// checkRowTypes validates a row before it is appended to block, see driver.WithStrictTypeCheck.
+func checkRowTypes(block *proto.Block, v []any) error {
+	if len(v) != len(block.Columns) {
+		// reported by block.Append
+		return nil
+	}
+	for i, col := range block.Columns {
+		if err := checkType(col, block.Rows(), v[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkType(col column.Interface, row int, v any) error {
+	if err := column.Validate(col, v); err != nil {
+		return &TypeCheckError{
+			Row:        row,
+			ColumnName: col.Name(),
+			ColumnType: string(col.Type()),
+			Err:        err,
+		}
+	}
+	return nil
+}
+
 var (
 	_ (driver.Batch)       = (*batch)(nil)
 	_ (driver.BatchColumn) = (*batchColumn)(nil)
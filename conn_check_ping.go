@@ -26,7 +26,9 @@ import (
 )
 
 func (c *connect) connCheck() error {
-	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second))
+	base, cancelBase := c.opt.withBaseContext(context.Background())
+	defer cancelBase()
+	ctx, cancel := context.WithDeadline(base, time.Now().Add(time.Second))
 	defer cancel()
 	if err := c.ping(ctx); err != nil {
 		return err
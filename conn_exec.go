@@ -19,8 +19,11 @@ package clickhouse
 
 import (
 	"context"
-	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+	"errors"
+	"regexp"
 	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
 func (c *connect) exec(ctx context.Context, query string, args ...any) error {
@@ -29,6 +32,7 @@ func (c *connect) exec(ctx context.Context, query string, args ...any) error {
 		queryParamsProtocolSupport = c.revision >= proto.DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS
 		body, err                  = bindQueryOrAppendParameters(queryParamsProtocolSupport, &options, query, c.server.Timezone, args...)
 	)
+	defer options.events.progressChan.close()
 	if err != nil {
 		return err
 	}
@@ -36,12 +40,43 @@ func (c *connect) exec(ctx context.Context, query string, args ...any) error {
 	c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
 	defer c.conn.SetReadDeadline(time.Time{})
 	// context level deadlines override any read deadline
-	if deadline, ok := ctx.Deadline(); ok {
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
 		c.conn.SetDeadline(deadline)
 		defer c.conn.SetDeadline(time.Time{})
 	}
-	if err := c.sendQuery(body, &options); err != nil {
+	if err := c.sendQuery(ctx, body, &options); err != nil {
 		return err
 	}
-	return c.process(ctx, options.onProcess())
+	on := options.onProcess()
+	if !insertSelectRe.MatchString(body) {
+		return c.process(ctx, on)
+	}
+	// close TCP connection on context cancel. Long-running queries such as INSERT INTO ... SELECT FROM s3(...)
+	// may not send any packet for a while, so the cancel would otherwise wait for the next one to be noticed.
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.Close()
+	})
+	if !hasDeadline {
+		// without a context deadline the read timeout applies between progress packets rather than to the whole query,
+		// so that a query reporting progress can run for longer than the read timeout
+		progress := on.progress
+		on.progress = func(p *Progress) {
+			c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+			progress(p)
+		}
+	}
+	err = c.process(ctx, on)
+	if !stop() {
+		// the connection is closed and must not be reused, report the cancel unless the server reported an error
+		var exception *Exception
+		if err == nil || !errors.As(err, &exception) {
+			return ctx.Err()
+		}
+	}
+	return err
 }
+
+// insertSelectRe matches INSERT ... SELECT queries, e.g. loading a table from the s3 or url table functions,
+// which may run for long without sending any data.
+var insertSelectRe = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s.*\bSELECT\b`)
@@ -0,0 +1,38 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertSelectRe(t *testing.T) {
+	for query, expected := range map[string]bool{
+		"INSERT INTO t SELECT * FROM s3('https://bucket/data.parquet')":          true,
+		" insert into t (a, b)\n\tselect a, b from url('https://host/data.csv')": true,
+		"INSERT INTO FUNCTION s3('https://bucket/out.parquet') SELECT * FROM t":  true,
+		"INSERT INTO t VALUES (1)":                   false,
+		"SELECT * FROM t":                            false,
+		"CREATE TABLE t AS SELECT 1":                 false,
+		"ALTER TABLE t DELETE WHERE a IN (SELECT 1)": false,
+	} {
+		assert.Equal(t, expected, insertSelectRe.MatchString(query), query)
+	}
+}
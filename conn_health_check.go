@@ -0,0 +1,71 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// HealthCheckConn is the connection checked by Options.HealthCheck. Its queries run on that connection only,
+// and the returned row must be scanned before the check returns.
+type HealthCheckConn interface {
+	Ping(ctx context.Context) error
+	QueryRow(ctx context.Context, query string, args ...any) driver.Row
+}
+
+type healthCheckConn struct {
+	conn stdConnect
+}
+
+func (h healthCheckConn) Ping(ctx context.Context) error {
+	return h.conn.ping(ctx)
+}
+
+func (h healthCheckConn) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	// a failing check closes the connection, it is never released
+	rows, err := h.conn.query(Context(ctx, ignoreProgressChan()), func(*connect, error) {}, query, args...)
+	if err != nil {
+		return &row{
+			err: err,
+		}
+	}
+	return &row{
+		rows: rows,
+	}
+}
+
+// healthCheck runs Options.HealthCheck on conn, bound by ctx and DialTimeout, unless it passed on conn less than
+// HealthCheckInterval ago. checkedAt is the time of the last check which passed.
+func healthCheck(ctx context.Context, opt *Options, conn stdConnect, checkedAt *time.Time) error {
+	if time.Since(*checkedAt) < opt.HealthCheckInterval {
+		return nil
+	}
+	// the context of the checkout carries the options of the caller's query, which the check must not use
+	ctx, cancelBase := opt.withBaseContext(Context(ctx, internalQuery()))
+	defer cancelBase()
+	ctx, cancel := context.WithTimeout(ctx, opt.DialTimeout)
+	defer cancel()
+	if err := opt.HealthCheck(ctx, healthCheckConn{conn: conn}); err != nil {
+		return err
+	}
+	*checkedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,73 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck(t *testing.T) {
+	var (
+		checks  int
+		failure error
+	)
+	opt := Options{
+		DialTimeout:         time.Second,
+		HealthCheckInterval: time.Hour,
+		HealthCheck: func(ctx context.Context, conn HealthCheckConn) error {
+			checks++
+			// the options of the caller's query are not used by the check, its settings are
+			o := queryOptions(ctx)
+			assert.Empty(t, o.queryID)
+			assert.Equal(t, 1, o.settings["max_threads"])
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return failure
+		},
+	}
+	ctx := Context(context.Background(), WithQueryID("query"), WithSettings(Settings{"max_threads": 1}))
+
+	var checkedAt time.Time
+	require.NoError(t, healthCheck(ctx, &opt, nil, &checkedAt))
+	assert.Equal(t, 1, checks)
+	assert.False(t, checkedAt.IsZero())
+
+	// a connection checked less than HealthCheckInterval ago is not checked again
+	failure = errors.New("replica is lagging")
+	require.NoError(t, healthCheck(ctx, &opt, nil, &checkedAt))
+	assert.Equal(t, 1, checks)
+
+	checkedAt = time.Time{}
+	assert.Equal(t, failure, healthCheck(ctx, &opt, nil, &checkedAt))
+	assert.Equal(t, 2, checks)
+	assert.True(t, checkedAt.IsZero())
+
+	// the check is bound by the context of the checkout
+	failure = nil
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, healthCheck(cancelled, &opt, nil, &checkedAt), context.Canceled)
+	assert.True(t, checkedAt.IsZero())
+}
@@ -248,6 +248,7 @@ func dialHttp(ctx context.Context, addr string, num int, opt *Options) (*httpCon
 		location:        location,
 		blockBufferSize: opt.BlockBufferSize,
 		headers:         headers,
+		opt:             opt,
 	}, nil
 }
 
@@ -261,14 +262,22 @@ type httpConnect struct {
 	compressionPool Pool[HTTPReaderWriter]
 	blockBufferSize uint8
 	headers         map[string]string
+	opt             *Options
+	healthCheckedAt time.Time
 }
 
-func (h *httpConnect) isBad() bool {
-	return h.client == nil
+func (h *httpConnect) isBad(ctx context.Context) bool {
+	if h.client == nil {
+		return true
+	}
+	if h.opt != nil && h.opt.HealthCheck != nil {
+		return healthCheck(ctx, h.opt, h, &h.healthCheckedAt) != nil
+	}
+	return false
 }
 
 func (h *httpConnect) readTimeZone(ctx context.Context) (*time.Location, error) {
-	rows, err := h.query(Context(ctx, ignoreExternalTables()), func(*connect, error) {}, "SELECT timezone()")
+	rows, err := h.query(Context(ctx, ignoreExternalTables(), ignoreProgressChan()), func(*connect, error) {}, "SELECT timezone()")
 	if err != nil {
 		return nil, err
 	}
@@ -290,7 +299,7 @@ func (h *httpConnect) readTimeZone(ctx context.Context) (*time.Location, error)
 }
 
 func (h *httpConnect) readVersion(ctx context.Context) (proto.Version, error) {
-	rows, err := h.query(Context(ctx, ignoreExternalTables()), func(*connect, error) {}, "SELECT version()")
+	rows, err := h.query(Context(ctx, ignoreExternalTables(), ignoreProgressChan()), func(*connect, error) {}, "SELECT version()")
 	if err != nil {
 		return proto.Version{}, err
 	}
@@ -361,6 +370,11 @@ func (h *httpConnect) writeData(block *proto.Block) error {
 	if err := block.Encode(h.buffer, 0); err != nil {
 		return err
 	}
+	return h.compressData(start)
+}
+
+// compressData compresses the buffer from offset start, if the connection uses LZ4 or ZSTD compression.
+func (h *httpConnect) compressData(start int) error {
 	if h.compression == CompressionLZ4 || h.compression == CompressionZSTD {
 		// Performing compression. Supported and requires
 		data := h.buffer.Buf[start:]
@@ -441,6 +455,9 @@ func (h *httpConnect) readRawResponse(response *http.Response) (body []byte, err
 }
 
 func (h *httpConnect) createRequest(ctx context.Context, requestUrl string, reader io.Reader, options *QueryOptions, headers map[string]string) (*http.Request, error) {
+	if options != nil && options.err != nil {
+		return nil, options.err
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestUrl, reader)
 	if err != nil {
 		return nil, err
@@ -448,15 +465,27 @@ func (h *httpConnect) createRequest(ctx context.Context, requestUrl string, read
 	for k, v := range headers {
 		req.Header.Add(k, v)
 	}
-	var query url.Values
+	query := req.URL.Query()
+	if h.opt != nil {
+		// settings of the query, set below, take precedence
+		for key, value := range h.opt.serverTimeoutSettings() {
+			query.Set(key, fmt.Sprint(value))
+		}
+	}
 	if options != nil {
-		query = req.URL.Query()
 		if options.queryID != "" {
 			query.Set(queryIDParamName, options.queryID)
 		}
 		if options.quotaKey != "" {
 			query.Set(quotaKeyParamName, options.quotaKey)
 		}
+		if span := options.spanContext(ctx); span.IsValid() {
+			// https://www.w3.org/TR/trace-context/#traceparent-header
+			req.Header.Set("traceparent", fmt.Sprintf("00-%s-%s-%s", span.TraceID(), span.SpanID(), span.TraceFlags()))
+			if state := span.TraceState().String(); state != "" {
+				req.Header.Set("tracestate", state)
+			}
+		}
 		for key, value := range options.settings {
 			// check that query doesn't change format
 			if key == "default_format" {
@@ -470,8 +499,8 @@ func (h *httpConnect) createRequest(ctx context.Context, requestUrl string, read
 		for key, value := range options.parameters {
 			query.Set(fmt.Sprintf("param_%s", key), value)
 		}
-		req.URL.RawQuery = query.Encode()
 	}
+	req.URL.RawQuery = query.Encode()
 	return req, nil
 }
 
@@ -541,7 +570,7 @@ func (h *httpConnect) executeRequest(req *http.Request) (*http.Response, error)
 }
 
 func (h *httpConnect) ping(ctx context.Context) error {
-	rows, err := h.query(Context(ctx, ignoreExternalTables()), nil, "SELECT 1")
+	rows, err := h.query(Context(ctx, ignoreExternalTables(), ignoreProgressChan()), nil, "SELECT 1")
 	if err != nil {
 		return err
 	}
@@ -25,6 +25,7 @@ import (
 func (h *httpConnect) asyncInsert(ctx context.Context, query string, wait bool, args ...any) error {
 
 	options := queryOptions(ctx)
+	defer options.events.progressChan.close()
 	options.settings["async_insert"] = 1
 	options.settings["wait_for_async_insert"] = 0
 	if wait {
@@ -44,6 +45,9 @@ func (h *httpConnect) asyncInsert(ctx context.Context, query string, wait bool,
 		// we don't care about result, so just discard it to reuse connection
 		_, _ = io.Copy(io.Discard, res.Body)
 	}
-
-	return err
+	if err != nil {
+		return err
+	}
+	h.insertAck(res, &options)
+	return nil
 }
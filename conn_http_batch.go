@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -34,7 +35,6 @@ import (
 var httpInsertRe = regexp.MustCompile(`(?i)^INSERT INTO\s+\x60?([\w.^\(]+)\x60?\s*(\([^\)]*\))?`)
 
 // release is ignored, because http used by std with empty release function.
-// Also opts ignored because all options unused in http batch.
 func (h *httpConnect) prepareBatch(ctx context.Context, query string, opts driver.PrepareBatchOptions, release func(*connect, error), acquire func(context.Context) (*connect, error)) (driver.Batch, error) {
 	matches := httpInsertRe.FindStringSubmatch(query)
 	if len(matches) < 3 {
@@ -51,7 +51,7 @@ func (h *httpConnect) prepareBatch(ctx context.Context, query string, opts drive
 	}
 	query = "INSERT INTO " + tableName + " FORMAT Native"
 	queryTableSchema := "DESCRIBE TABLE " + tableName
-	r, err := h.query(ctx, release, queryTableSchema)
+	r, err := h.query(Context(ctx, ignoreProgressChan()), release, queryTableSchema)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +60,9 @@ func (h *httpConnect) prepareBatch(ctx context.Context, query string, opts drive
 
 	// get Table columns and types
 	columns := make(map[string]string)
-	var colNames []string
+	defaults := make(map[string]bool)
+	defaultTypes := make(map[string]string)
+	var colNames, allColNames []string
 	for r.Next() {
 		var (
 			colName      string
@@ -72,12 +74,21 @@ func (h *httpConnect) prepareBatch(ctx context.Context, query string, opts drive
 		if err = r.Scan(&colName, &colType, &default_type, &ignore, &ignore, &ignore, &ignore); err != nil {
 			return nil, err
 		}
+		allColNames = append(allColNames, colName)
+		defaultTypes[colName] = default_type
 		// these column types cannot be specified in INSERT queries
 		if default_type == "MATERIALIZED" || default_type == "ALIAS" {
 			continue
 		}
 		colNames = append(colNames, colName)
 		columns[colName] = colType
+		defaults[colName] = default_type == "DEFAULT"
+	}
+
+	if opts.OmitDefaultColumns && len(rColumns) != 0 {
+		if err = checkOmittedColumnDefaults(tableName, rColumns, allColNames, defaultTypes); err != nil {
+			return nil, err
+		}
 	}
 
 	switch len(rColumns) {
@@ -100,23 +111,45 @@ func (h *httpConnect) prepareBatch(ctx context.Context, query string, opts drive
 		}
 	}
 
-	return &httpBatch{
-		ctx:       ctx,
-		conn:      h,
-		structMap: &structMap{},
-		block:     block,
-		query:     query,
-	}, nil
+	b := &httpBatch{
+		ctx:          ctx,
+		conn:         h,
+		structMap:    &structMap{},
+		block:        block,
+		query:        query,
+		progressChan: queryOptions(ctx).events.progressChan,
+		strict:       opts.StrictTypeCheck,
+	}
+	if opts.RowBinaryWithDefaults {
+		// RowBinary values are matched by position, so the column list must be part of the query
+		names := make([]string, 0, len(block.Columns))
+		for _, name := range block.ColumnsNames() {
+			names = append(names, "`"+strings.ReplaceAll(name, "`", "\\`")+"`")
+			b.hasDefault = append(b.hasDefault, defaults[name])
+		}
+		b.query = "INSERT INTO " + tableName + " (" + strings.Join(names, ", ") + ") FORMAT RowBinaryWithDefaults"
+		b.rowBinaryWithDefaults = true
+		b.useDefault = make([][]bool, len(block.Columns))
+		b.defaultRows = make([]int, len(block.Columns))
+	}
+	return b, nil
 }
 
 type httpBatch struct {
-	query     string
-	err       error
-	ctx       context.Context
-	conn      *httpConnect
-	structMap *structMap
-	sent      bool
-	block     *proto.Block
+	query        string
+	err          error
+	ctx          context.Context
+	conn         *httpConnect
+	structMap    *structMap
+	sent         bool
+	block        *proto.Block
+	progressChan *progressChan
+
+	strict                bool
+	rowBinaryWithDefaults bool
+	hasDefault            []bool   // per column, whether the column has a DEFAULT expression
+	useDefault            [][]bool // per column, the rows which are replaced by the column default
+	defaultRows           []int    // per column, the number of rows which are replaced by the column default
 }
 
 // Flush TODO: noop on http currently - requires streaming to be implemented
@@ -127,6 +160,7 @@ func (b *httpBatch) Flush() error {
 func (b *httpBatch) Abort() error {
 	defer func() {
 		b.sent = true
+		b.progressChan.close()
 	}()
 	if b.sent {
 		return ErrBatchAlreadySent
@@ -138,12 +172,125 @@ func (b *httpBatch) Append(v ...any) error {
 	if b.sent {
 		return ErrBatchAlreadySent
 	}
+	if b.rowBinaryWithDefaults {
+		return b.appendWithDefaults(v...)
+	}
+	if b.strict {
+		if err := checkRowTypes(b.block, v); err != nil {
+			return err
+		}
+	}
 	if err := b.block.Append(v...); err != nil {
 		return err
 	}
 	return nil
 }
 
+// appendWithDefaults appends a row where nil values of columns with a DEFAULT expression are
+// replaced by the server with the column default. Such values are not appended to their column,
+// since they are not sent, so that no placeholder has to be valid for the column type.
+func (b *httpBatch) appendWithDefaults(v ...any) error {
+	if len(v) != len(b.block.Columns) {
+		return b.block.Append(v...)
+	}
+	useDefault := make([]bool, len(v))
+	for i, value := range v {
+		col := b.block.Columns[i]
+		if value != nil && !isNilPtr(value) {
+			continue
+		}
+		switch {
+		case b.hasDefault[i]:
+			useDefault[i] = true
+		case !strings.HasPrefix(string(col.Type()), "Nullable(") && !strings.HasPrefix(string(col.Type()), "LowCardinality(Nullable("):
+			return &OpError{
+				Op:         "AppendRow",
+				ColumnName: col.Name(),
+				Err:        fmt.Errorf("nil value for column %s which has no DEFAULT expression and is not Nullable", col.Name()),
+			}
+		}
+	}
+	if b.strict {
+		for i, col := range b.block.Columns {
+			if useDefault[i] {
+				continue
+			}
+			if err := checkType(col, col.Rows(), v[i]); err != nil {
+				return err
+			}
+		}
+	}
+	for i, col := range b.block.Columns {
+		if useDefault[i] {
+			continue
+		}
+		if err := col.AppendRow(v[i]); err != nil {
+			return &OpError{
+				Op:         "AppendRow",
+				ColumnName: col.Name(),
+				Err:        err,
+			}
+		}
+	}
+	for i, col := range b.block.Columns {
+		b.padUseDefault(i, col.Rows())
+		if useDefault[i] {
+			b.useDefault[i] = append(b.useDefault[i], true)
+			b.defaultRows[i]++
+		}
+	}
+	return nil
+}
+
+// padUseDefault records that the rows of column i up to rows, e.g. appended through Column(idx),
+// do not use the default.
+func (b *httpBatch) padUseDefault(i int, rows int) {
+	for len(b.useDefault[i])-b.defaultRows[i] < rows {
+		b.useDefault[i] = append(b.useDefault[i], false)
+	}
+}
+
+func isNilPtr(v any) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+func (b *httpBatch) writeRowBinaryWithDefaults() error {
+	var (
+		start   = len(b.conn.buffer.Buf)
+		encoder = column.NewRowBinaryEncoder()
+		colRows = make([]int, len(b.block.Columns))
+	)
+	for i, col := range b.block.Columns {
+		b.padUseDefault(i, col.Rows())
+	}
+	for row := 0; row < b.Rows(); row++ {
+		for i, col := range b.block.Columns {
+			if row < len(b.useDefault[i]) && b.useDefault[i][row] {
+				b.conn.buffer.PutByte(1)
+				continue
+			}
+			if colRows[i] >= col.Rows() {
+				return &OpError{
+					Op:         "Send",
+					ColumnName: col.Name(),
+					Err:        fmt.Errorf("column %s has fewer rows than the batch (%d)", col.Name(), b.Rows()),
+				}
+			}
+			b.conn.buffer.PutByte(0)
+			if err := encoder.Encode(col, colRows[i], b.conn.buffer); err != nil {
+				return &OpError{
+					Op:         "Send",
+					ColumnName: col.Name(),
+					Err:        err,
+				}
+			}
+			colRows[i]++
+		}
+	}
+	return b.conn.compressData(start)
+}
+
 func (b *httpBatch) AppendStruct(v any) error {
 	values, err := b.structMap.Map("AppendStruct", b.block.ColumnsNames(), v, false)
 	if err != nil {
@@ -164,6 +311,7 @@ func (b *httpBatch) Column(idx int) driver.BatchColumn {
 	return &batchColumn{
 		batch:  b,
 		column: b.block.Columns[idx],
+		strict: b.strict,
 		release: func(err error) {
 			b.err = err
 		},
@@ -177,6 +325,7 @@ func (b *httpBatch) IsSent() bool {
 func (b *httpBatch) Send() (err error) {
 	defer func() {
 		b.sent = true
+		b.progressChan.close()
 	}()
 	if b.sent {
 		return ErrBatchAlreadySent
@@ -186,6 +335,15 @@ func (b *httpBatch) Send() (err error) {
 	}
 	options := queryOptions(b.ctx)
 
+	if b.rowBinaryWithDefaults {
+		// encoded upfront, so that encoding errors are returned instead of sending a partial body
+		b.conn.buffer.Reset()
+		if err := b.writeRowBinaryWithDefaults(); err != nil {
+			b.conn.buffer.Reset()
+			return err
+		}
+	}
+
 	headers := make(map[string]string)
 
 	r, pw := io.Pipe()
@@ -205,6 +363,10 @@ func (b *httpBatch) Send() (err error) {
 		var err error = nil
 		defer pw.CloseWithError(err)
 		defer w.Close()
+		if b.rowBinaryWithDefaults {
+			_, err = w.Write(b.conn.buffer.Buf)
+			return
+		}
 		b.conn.buffer.Reset()
 		if b.block.Rows() != 0 {
 			if err = b.conn.writeData(b.block); err != nil {
@@ -231,11 +393,22 @@ func (b *httpBatch) Send() (err error) {
 		// we don't care about result, so just discard it to reuse connection
 		_, _ = io.Copy(io.Discard, res.Body)
 	}
-
-	return err
+	if err != nil {
+		return err
+	}
+	b.conn.insertAck(res, &options)
+	return nil
 }
 
 func (b *httpBatch) Rows() int {
+	if b.rowBinaryWithDefaults {
+		// columns do not hold the rows replaced by their default
+		var rows int
+		for i, col := range b.block.Columns {
+			rows = max(rows, col.Rows()+b.defaultRows[i])
+		}
+		return rows
+	}
 	return b.block.Rows()
 }
 
@@ -0,0 +1,57 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"testing"
+
+	chproto "github.com/ClickHouse/ch-go/proto"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpBatchRowBinaryWithDefaults(t *testing.T) {
+	block := &proto.Block{}
+	require.NoError(t, block.AddColumn("e", column.Type("Enum8('a' = 1, 'b' = 2)")))
+	require.NoError(t, block.AddColumn("t", column.Type("Tuple(UInt8, String)")))
+	require.NoError(t, block.AddColumn("id", column.Type("UInt8")))
+	b := &httpBatch{
+		ctx:                   context.Background(),
+		conn:                  &httpConnect{buffer: new(chproto.Buffer)},
+		structMap:             &structMap{},
+		block:                 block,
+		rowBinaryWithDefaults: true,
+		hasDefault:            []bool{true, true, false},
+		useDefault:            make([][]bool, 3),
+		defaultRows:           make([]int, 3),
+	}
+	// Enum and Tuple columns have no zero value valid for their type, defaults are not appended to them
+	require.NoError(t, b.Append(nil, nil, uint8(1)))
+	require.NoError(t, b.Append("b", []any{uint8(2), "x"}, uint8(2)))
+	assert.Error(t, b.Append("a", nil, nil))
+	assert.Equal(t, 2, b.Rows())
+
+	require.NoError(t, b.writeRowBinaryWithDefaults())
+	assert.Equal(t, []byte{
+		1, 1, 0, 1,
+		0, 2, 0, 2, 1, 'x', 0, 2,
+	}, b.conn.buffer.Buf)
+}
@@ -24,6 +24,7 @@ import (
 
 func (h *httpConnect) exec(ctx context.Context, query string, args ...any) error {
 	options := queryOptions(ctx)
+	defer options.events.progressChan.close()
 	query, err := bindQueryOrAppendParameters(true, &options, query, h.location, args...)
 	if err != nil {
 		return err
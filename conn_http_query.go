@@ -52,6 +52,7 @@ func (h *httpConnect) query(ctx context.Context, release func(*connect, error),
 	}
 
 	if res.ContentLength == 0 {
+		options.events.progressChan.close()
 		block := &proto.Block{}
 		return &rows{
 			block:     block,
@@ -110,6 +111,7 @@ func (h *httpConnect) query(ctx context.Context, release func(*connect, error),
 		h.compressionPool.Put(rw)
 		close(stream)
 		close(errCh)
+		options.events.progressChan.close()
 	}()
 
 	if block == nil {
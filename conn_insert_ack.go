@@ -0,0 +1,107 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// InsertAck is the acknowledgment of an insert by the server, see WithInsertAck.
+type InsertAck struct {
+	WrittenRows  uint64
+	WrittenBytes uint64
+	// Unknown is set when the server acknowledged the insert without reporting the rows and bytes it wrote,
+	// e.g. over HTTP when the X-ClickHouse-Summary header is missing. WrittenRows and WrittenBytes are then zero.
+	Unknown bool
+}
+
+// acknowledgesInsert reports whether the server only answers an insert once its data is written. Asynchronous
+// inserts are acknowledged when wait_for_async_insert is enabled for the query or the client. Other inserts are
+// acknowledged at the end of the query on the native protocol, and over HTTP when wait_end_of_query is enabled.
+func (q *QueryOptions) acknowledgesInsert(clientSettings Settings, http bool) bool {
+	enabled := func(name string) bool {
+		value, ok := q.settings[name]
+		if !ok {
+			value, ok = clientSettings[name]
+		}
+		return ok && isEnabledSetting(value)
+	}
+	switch {
+	case enabled("async_insert"):
+		return enabled("wait_for_async_insert")
+	case http:
+		return enabled("wait_end_of_query")
+	}
+	return true
+}
+
+func isEnabledSetting(value any) bool {
+	if cv, ok := value.(CustomSetting); ok {
+		value = cv.Value
+	}
+	switch v := strings.ToLower(fmt.Sprint(value)); v {
+	case "1", "true":
+		return true
+	}
+	return false
+}
+
+// trackInsertAck sums the rows and bytes written by the server, as reported by the progress packets
+// of the native protocol, into ack.
+func trackInsertAck(on *onProcess, ack *InsertAck) {
+	progress := on.progress
+	on.progress = func(p *Progress) {
+		ack.WrittenRows += p.WroteRows
+		ack.WrittenBytes += p.WroteBytes
+		if progress != nil {
+			progress(p)
+		}
+	}
+}
+
+// insertSummary reads the rows and bytes written by the server from the X-ClickHouse-Summary header of res.
+// The insert has succeeded already, so a missing or invalid header is reported as an unknown count.
+func insertSummary(res *http.Response) *InsertAck {
+	var summary struct {
+		WrittenRows  uint64 `json:"written_rows,string"`
+		WrittenBytes uint64 `json:"written_bytes,string"`
+	}
+	header := res.Header.Get("X-ClickHouse-Summary")
+	if header == "" || json.Unmarshal([]byte(header), &summary) != nil {
+		return &InsertAck{Unknown: true}
+	}
+	return &InsertAck{
+		WrittenRows:  summary.WrittenRows,
+		WrittenBytes: summary.WrittenBytes,
+	}
+}
+
+// insertAck calls the WithInsertAck callback of an insert acknowledged by res.
+func (h *httpConnect) insertAck(res *http.Response, options *QueryOptions) {
+	var clientSettings Settings
+	if h.opt != nil {
+		clientSettings = h.opt.Settings
+	}
+	if options.events.insertAck == nil || !options.acknowledgesInsert(clientSettings, true) {
+		return
+	}
+	options.events.insertAck(insertSummary(res))
+}
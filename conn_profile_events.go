@@ -34,6 +34,60 @@ type ProfileEvent struct {
 	Value       int64
 }
 
+const (
+	ProfileEventIncrement = "increment"
+	ProfileEventGauge     = "gauge"
+)
+
+// ProfileEventsUpdate is a single ProfileEvents packet received while a query runs, along with the
+// cumulative values of the query so far.
+type ProfileEventsUpdate struct {
+	// Events of the packet. Increment events hold the delta since the previous packet,
+	// gauge events hold the current value.
+	Events []ProfileEvent
+	// Totals by event name: the sum of all increments received so far and, for gauges, the sum over
+	// hosts of the latest value. The map is not reused between updates.
+	Totals map[string]int64
+}
+
+type profileEventsTotals struct {
+	increments map[string]int64
+	gauges     map[string]map[string]int64
+}
+
+func (t *profileEventsTotals) update(events []ProfileEvent) *ProfileEventsUpdate {
+	if t.increments == nil {
+		t.increments = make(map[string]int64)
+		t.gauges = make(map[string]map[string]int64)
+	}
+	for _, event := range events {
+		switch event.Type {
+		case ProfileEventGauge:
+			hosts, ok := t.gauges[event.Name]
+			if !ok {
+				hosts = make(map[string]int64)
+				t.gauges[event.Name] = hosts
+			}
+			hosts[event.Hostname] = event.Value
+		default:
+			t.increments[event.Name] += event.Value
+		}
+	}
+	totals := make(map[string]int64, len(t.increments)+len(t.gauges))
+	for name, value := range t.increments {
+		totals[name] = value
+	}
+	for name, hosts := range t.gauges {
+		for _, value := range hosts {
+			totals[name] += value
+		}
+	}
+	return &ProfileEventsUpdate{
+		Events: events,
+		Totals: totals,
+	}
+}
+
 func (c *connect) profileEvents(ctx context.Context) ([]ProfileEvent, error) {
 	block, err := c.readData(ctx, proto.ServerProfileEvents, false)
 	if err != nil {
@@ -47,7 +47,7 @@ func (c *connect) query(ctx context.Context, release func(*connect, error), quer
 		defer c.conn.SetDeadline(time.Time{})
 	}
 
-	if err = c.sendQuery(body, &options); err != nil {
+	if err = c.sendQuery(ctx, body, &options); err != nil {
 		release(c, err)
 		return nil, err
 	}
@@ -80,6 +80,7 @@ func (c *connect) query(ctx context.Context, release func(*connect, error), quer
 		}
 		close(stream)
 		close(errors)
+		options.events.progressChan.close()
 		release(c, err)
 	}()
 
@@ -18,12 +18,17 @@
 package clickhouse
 
 import (
+	"context"
+
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
 // Connection::sendQuery
 // https://github.com/ClickHouse/ClickHouse/blob/master/src/Client/Connection.cpp
-func (c *connect) sendQuery(body string, o *QueryOptions) error {
+func (c *connect) sendQuery(ctx context.Context, body string, o *QueryOptions) error {
+	if o.err != nil {
+		return o.err
+	}
 	c.debugf("[send query] compression=%q %s", c.compression, body)
 	c.buffer.PutByte(proto.ClientQuery)
 	q := proto.Query{
@@ -32,7 +37,7 @@ func (c *connect) sendQuery(body string, o *QueryOptions) error {
 		ClientVersion:            proto.Version{ClientVersionMajor, ClientVersionMinor, ClientVersionPatch}, //nolint:govet
 		ID:                       o.queryID,
 		Body:                     body,
-		Span:                     o.span,
+		Span:                     o.spanContext(ctx),
 		QuotaKey:                 o.quotaKey,
 		Compression:              c.compression != CompressionNone,
 		InitialAddress:           c.conn.LocalAddr().String(),
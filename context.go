@@ -19,6 +19,9 @@ package clickhouse
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/ext"
@@ -54,17 +57,28 @@ type (
 		events   struct {
 			logs          func(*Log)
 			progress      func(*Progress)
+			progressChan  *progressChan
 			profileInfo   func(*ProfileInfo)
 			profileEvents func([]ProfileEvent)
+			profileStream func(*ProfileEventsUpdate)
+			insertAck     func(*InsertAck)
 		}
 		settings        Settings
+		guards          Settings
 		parameters      Parameters
 		external        []*ext.Table
 		blockBufferSize uint8
 		userLocation    *time.Location
+
+		stdRowBinaryWithDefaults bool
+
+		err error // the first error returned by an option passed to Context, reported when the query is sent
 	}
 )
 
+// WithSpan sets the trace context sent to the server, which records the spans of the query under it: in the
+// client info on the native protocol and as the traceparent and tracestate headers over HTTP. Without it, the
+// span of the context passed to the query is sent, if any.
 func WithSpan(span trace.SpanContext) QueryOption {
 	return func(o *QueryOptions) error {
 		o.span = span
@@ -121,6 +135,20 @@ func WithProgress(fn func(*Progress)) QueryOption {
 	}
 }
 
+// WithProgressChan delivers query progress to ch, as an alternative to WithProgress. Events are sent
+// without blocking the query: if ch is full when progress arrives, the event is dropped. Progress values
+// are deltas, so dropped events are lost from any running total. ch is closed by the driver when the first
+// query run with the context ends, therefore it must not be closed by the caller and the context is single-use:
+// later queries run with it report no progress. Queries the driver runs internally, e.g. to describe the table
+// of a batch, do not use ch. Progress is only reported on the native protocol, over HTTP ch is closed without
+// receiving any events.
+func WithProgressChan(ch chan<- Progress) QueryOption {
+	return func(o *QueryOptions) error {
+		o.events.progressChan = &progressChan{ch: ch}
+		return nil
+	}
+}
+
 func WithProfileInfo(fn func(*ProfileInfo)) QueryOption {
 	return func(o *QueryOptions) error {
 		o.events.profileInfo = fn
@@ -135,6 +163,129 @@ func WithProfileEvents(fn func([]ProfileEvent)) QueryOption {
 	}
 }
 
+// WithStreamingProfileEvents calls fn for every ProfileEvents packet as it arrives during the query,
+// with the packet events and the cumulative totals of the query so far. The server sends these packets
+// periodically, so fn can feed live metrics of long running queries. Profile events are only reported
+// on the native protocol, over HTTP fn is never called.
+func WithStreamingProfileEvents(fn func(*ProfileEventsUpdate)) QueryOption {
+	return func(o *QueryOptions) error {
+		o.events.profileStream = fn
+		return nil
+	}
+}
+
+// WithInsertAck calls fn once a batch is sent or an AsyncInsert returns, with the rows and bytes the server reports
+// as written: from the progress packets on the native protocol and from the X-ClickHouse-Summary header over HTTP.
+// fn is only called when the server answers after the data is written, so that a producer can commit its offsets
+// once fn is called: asynchronous inserts need the wait_for_async_insert setting, other inserts are acknowledged
+// on the native protocol and need the wait_end_of_query setting over HTTP. An insert acknowledged without a summary
+// is reported with InsertAck.Unknown set rather than failing.
+func WithInsertAck(fn func(*InsertAck)) QueryOption {
+	return func(o *QueryOptions) error {
+		o.events.insertAck = fn
+		return nil
+	}
+}
+
+// ReadonlyMode is the value of the readonly setting applied to a query.
+type ReadonlyMode uint8
+
+const (
+	// ReadonlyDisabled allows read, write and settings change queries.
+	ReadonlyDisabled ReadonlyMode = iota
+	// ReadonlyStrict allows only read queries. Settings cannot be changed for the rest of the query.
+	ReadonlyStrict
+	// ReadonlyAllowSettings allows read and settings change queries.
+	ReadonlyAllowSettings
+)
+
+// WithAllowDDL sets the allow_ddl setting for the query. When false, the server rejects
+// DDL statements (CREATE, ALTER, DROP, ...) with code 392 (QUERY_IS_PROHIBITED) and refuses
+// any attempt to re-enable DDL from within the query. On the native protocol the error is returned
+// as *Exception, over HTTP its code and message are part of the returned error text. Guard settings
+// take precedence over the same settings given with WithSettings, whatever the order of the options.
+func WithAllowDDL(allow bool) QueryOption {
+	return func(o *QueryOptions) error {
+		v := 1
+		if !allow {
+			v = 0
+		}
+		o.setGuard("allow_ddl", v)
+		return nil
+	}
+}
+
+// WithReadonly sets the readonly setting for the query. Writes and DDL are rejected by the server
+// with code 164 (READONLY). ReadonlyAllowSettings should be preferred over ReadonlyStrict, since
+// the client sends its own settings (e.g. max_execution_time from the context deadline) along with the query.
+func WithReadonly(mode ReadonlyMode) QueryOption {
+	return func(o *QueryOptions) error {
+		o.setGuard("readonly", int(mode))
+		return nil
+	}
+}
+
+// WithAllowIntrospectionFunctions sets the allow_introspection_functions setting for the query.
+func WithAllowIntrospectionFunctions(allow bool) QueryOption {
+	return func(o *QueryOptions) error {
+		v := 0
+		if allow {
+			v = 1
+		}
+		o.setGuard("allow_introspection_functions", v)
+		return nil
+	}
+}
+
+// ResourceLimits is a bundle of query complexity limits, see WithResourceLimits. Zero fields are not set.
+type ResourceLimits struct {
+	// MaxRowsToRead sets max_rows_to_read, the number of rows which can be read from tables.
+	MaxRowsToRead uint64
+	// MaxBytesToRead sets max_bytes_to_read, the number of uncompressed bytes which can be read from tables.
+	MaxBytesToRead uint64
+	// MaxResultRows sets max_result_rows, the number of rows of the result.
+	MaxResultRows uint64
+	// MaxExecutionTime sets max_execution_time, rounded up to whole seconds.
+	MaxExecutionTime time.Duration
+}
+
+// WithResourceLimits sets the complexity limits of the query at once, leaving the settings of zero
+// fields untouched. The server rejects a query exceeding a limit with code 158 (TOO_MANY_ROWS), 307
+// (TOO_MANY_BYTES), 396 (TOO_MANY_ROWS_OR_BYTES) or 159 (TIMEOUT_EXCEEDED). A context deadline shorter than
+// MaxExecutionTime still takes precedence. Invalid limits set none of them and fail the queries run with
+// the context, see Context.
+func WithResourceLimits(limits ResourceLimits) QueryOption {
+	return func(o *QueryOptions) error {
+		if limits.MaxExecutionTime < 0 {
+			return fmt.Errorf("invalid MaxExecutionTime %s: must not be negative", limits.MaxExecutionTime)
+		}
+		counts := []struct {
+			setting string
+			value   uint64
+		}{
+			{"max_rows_to_read", limits.MaxRowsToRead},
+			{"max_bytes_to_read", limits.MaxBytesToRead},
+			{"max_result_rows", limits.MaxResultRows},
+		}
+		for _, count := range counts {
+			// settings are sent as int, see proto.Setting
+			if count.value > math.MaxInt64 {
+				return fmt.Errorf("invalid %s %d: out of range", count.setting, count.value)
+			}
+		}
+		for _, count := range counts {
+			if count.value != 0 {
+				o.setSetting(count.setting, int(count.value))
+			}
+		}
+		if limits.MaxExecutionTime != 0 {
+			seconds := (limits.MaxExecutionTime + time.Second - 1) / time.Second
+			o.setSetting("max_execution_time", int(seconds))
+		}
+		return nil
+	}
+}
+
 func WithExternalTable(t ...*ext.Table) QueryOption {
 	return func(o *QueryOptions) error {
 		o.external = append(o.external, t...)
@@ -149,6 +300,17 @@ func WithStdAsync(wait bool) QueryOption {
 	}
 }
 
+// WithStdRowBinaryWithDefaults makes statements prepared through database/sql send their rows in the
+// RowBinaryWithDefaults format. A nil value appended to a column with a DEFAULT expression is then replaced
+// by the server with the column default. Only supported by the HTTP protocol, preparing a statement over
+// the native protocol fails with ErrUnsupportedBatchFormat.
+func WithStdRowBinaryWithDefaults() QueryOption {
+	return func(o *QueryOptions) error {
+		o.stdRowBinaryWithDefaults = true
+		return nil
+	}
+}
+
 func WithUserLocation(location *time.Location) QueryOption {
 	return func(o *QueryOptions) error {
 		o.userLocation = location
@@ -163,29 +325,97 @@ func ignoreExternalTables() QueryOption {
 	}
 }
 
+func ignoreProgressChan() QueryOption {
+	return func(o *QueryOptions) error {
+		o.events.progressChan = nil
+		return nil
+	}
+}
+
+// internalQuery clears the options bound to a single query of the caller, e.g. its query id, events or
+// parameters, for a query the driver runs on its own. Settings and the span still apply.
+func internalQuery() QueryOption {
+	return func(o *QueryOptions) error {
+		var clear QueryOptions
+		o.async = clear.async
+		o.queryID = clear.queryID
+		o.events = clear.events
+		o.parameters = nil
+		o.external = nil
+		o.blockBufferSize = 0
+		o.stdRowBinaryWithDefaults = false
+		o.err = nil
+		return nil
+	}
+}
+
+// Context returns a copy of parent carrying the query options. An option returning an error, e.g. WithResourceLimits
+// given invalid limits, does not apply and the queries run with the returned context fail with its error.
 func Context(parent context.Context, options ...QueryOption) context.Context {
 	opt := queryOptions(parent)
 	for _, f := range options {
-		f(&opt)
+		if err := f(&opt); err != nil && opt.err == nil {
+			opt.err = err
+		}
 	}
 	return context.WithValue(parent, _contextOptionKey, opt)
 }
 
 func queryOptions(ctx context.Context) QueryOptions {
-	if o, ok := ctx.Value(_contextOptionKey).(QueryOptions); ok {
+	o, ok := ctx.Value(_contextOptionKey).(QueryOptions)
+	if ok {
 		if deadline, ok := ctx.Deadline(); ok {
 			if sec := time.Until(deadline).Seconds(); sec > 1 {
-				o.settings["max_execution_time"] = int(sec + 5)
+				// a lower limit, e.g. from WithResourceLimits, is kept
+				if limit, ok := o.settings["max_execution_time"].(int); !ok || limit <= 0 || limit > int(sec+5) {
+					o.setSetting("max_execution_time", int(sec+5))
+				}
 			}
 		}
-		return o
+		for key, value := range o.guards {
+			// guards apply last, so that WithSettings cannot lift them
+			o.setSetting(key, value)
+		}
+	} else {
+		o = QueryOptions{
+			settings: make(Settings),
+		}
 	}
-	return QueryOptions{
-		settings: make(Settings),
+	return o
+}
+
+// spanContext returns the span set with WithSpan, or else the active span of ctx, the context of the query.
+// The active span is looked up when the query is sent, so that the span active when Context was called is not.
+func (q *QueryOptions) spanContext(ctx context.Context) trace.SpanContext {
+	if q.span.IsValid() {
+		return q.span
+	}
+	return trace.SpanContextFromContext(ctx)
+}
+
+// setSetting sets a single setting on a copy of the settings map, so that a context derived
+// with Context does not change the settings of its parent.
+func (q *QueryOptions) setSetting(key string, value any) {
+	settings := make(Settings, len(q.settings)+1)
+	for k, v := range q.settings {
+		settings[k] = v
+	}
+	settings[key] = value
+	q.settings = settings
+}
+
+// setGuard sets a guard setting, which overrides the settings of the query, see WithAllowDDL.
+func (q *QueryOptions) setGuard(key string, value any) {
+	guards := make(Settings, len(q.guards)+1)
+	for k, v := range q.guards {
+		guards[k] = v
 	}
+	guards[key] = value
+	q.guards = guards
 }
 
 func (q *QueryOptions) onProcess() *onProcess {
+	var totals profileEventsTotals
 	return &onProcess{
 		logs: func(logs []Log) {
 			if q.events.logs != nil {
@@ -198,6 +428,7 @@ func (q *QueryOptions) onProcess() *onProcess {
 			if q.events.progress != nil {
 				q.events.progress(p)
 			}
+			q.events.progressChan.send(p)
 		},
 		profileInfo: func(p *ProfileInfo) {
 			if q.events.profileInfo != nil {
@@ -208,6 +439,50 @@ func (q *QueryOptions) onProcess() *onProcess {
 			if q.events.profileEvents != nil {
 				q.events.profileEvents(events)
 			}
+			if q.events.profileStream != nil {
+				q.events.profileStream(totals.update(events))
+			}
 		},
 	}
 }
+
+// closeProgressChanOnError closes the progress channel of the query in ctx when an entry point fails with err,
+// since a query failing before it runs, e.g. when no connection can be acquired, has no other path closing it.
+func closeProgressChanOnError(ctx context.Context, err *error) {
+	if *err != nil {
+		queryOptions(ctx).events.progressChan.close()
+	}
+}
+
+type progressChan struct {
+	mu     sync.Mutex
+	ch     chan<- Progress
+	closed bool
+}
+
+func (p *progressChan) send(progress *Progress) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.ch <- *progress:
+	default:
+	}
+}
+
+func (p *progressChan) close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.ch)
+	}
+}
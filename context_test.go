@@ -19,9 +19,15 @@ package clickhouse
 
 import (
 	"context"
+	"math"
+	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestContext(t *testing.T) {
@@ -50,3 +56,281 @@ func TestContext(t *testing.T) {
 		},
 	)
 }
+
+func TestContextGuardSettings(t *testing.T) {
+	parent := Context(context.Background(), WithSettings(Settings{
+		"max_threads": 4,
+	}))
+	ctx := Context(parent, WithAllowDDL(false), WithReadonly(ReadonlyAllowSettings), WithAllowIntrospectionFunctions(false))
+
+	opts := queryOptions(ctx)
+	assert.Equal(t, 0, opts.settings["allow_ddl"])
+	assert.Equal(t, 2, opts.settings["readonly"])
+	assert.Equal(t, 0, opts.settings["allow_introspection_functions"])
+	assert.Equal(t, 4, opts.settings["max_threads"])
+
+	// guard settings only apply to the query they were set for
+	parentOpts := queryOptions(parent)
+	assert.NotContains(t, parentOpts.settings, "allow_ddl")
+	assert.NotContains(t, parentOpts.settings, "readonly")
+
+	h := &httpConnect{url: &url.URL{Scheme: "http", Host: "localhost:8123"}}
+	req, err := h.prepareRequest(ctx, "CREATE TABLE t (x UInt8) ENGINE = Memory", &opts, map[string]string{})
+	require.NoError(t, err)
+	query := req.URL.Query()
+	assert.Equal(t, "0", query.Get("allow_ddl"))
+	assert.Equal(t, "2", query.Get("readonly"))
+	assert.Equal(t, "0", query.Get("allow_introspection_functions"))
+
+	// guard settings are not lifted by settings replaced afterwards
+	ctx = Context(context.Background(), WithAllowDDL(false), WithSettings(Settings{
+		"allow_ddl":   1,
+		"max_threads": 4,
+	}))
+	opts = queryOptions(ctx)
+	assert.Equal(t, 0, opts.settings["allow_ddl"])
+	assert.Equal(t, 4, opts.settings["max_threads"])
+	ctx = Context(ctx, WithSettings(Settings{"readonly": 0}))
+	assert.Equal(t, 0, queryOptions(ctx).settings["allow_ddl"])
+}
+
+func TestContextProgressChan(t *testing.T) {
+	progress := make(chan Progress, 1)
+	opts := queryOptions(Context(context.Background(), WithProgressChan(progress)))
+	on := opts.onProcess()
+
+	on.progress(&Progress{Rows: 1})
+	// the channel is full, so the event is dropped instead of blocking the query
+	on.progress(&Progress{Rows: 2})
+	opts.events.progressChan.close()
+	// the query may end through several paths, closing more than once is safe
+	opts.events.progressChan.close()
+	on.progress(&Progress{Rows: 3})
+
+	var received []uint64
+	for p := range progress {
+		received = append(received, p.Rows)
+	}
+	assert.Equal(t, []uint64{1}, received)
+
+	internal := queryOptions(Context(Context(context.Background(), WithProgressChan(progress)), ignoreProgressChan()))
+	assert.Nil(t, internal.events.progressChan)
+}
+
+func TestContextStreamingProfileEvents(t *testing.T) {
+	var updates []*ProfileEventsUpdate
+	ctx := Context(context.Background(), WithStreamingProfileEvents(func(u *ProfileEventsUpdate) {
+		updates = append(updates, u)
+	}))
+	opts := queryOptions(ctx)
+	on := opts.onProcess()
+	on.profileEvents([]ProfileEvent{
+		{Hostname: "a", Type: ProfileEventIncrement, Name: "SelectedRows", Value: 10},
+		{Hostname: "b", Type: ProfileEventIncrement, Name: "SelectedRows", Value: 5},
+		{Hostname: "a", Type: ProfileEventGauge, Name: "MemoryTrackerUsage", Value: 100},
+		{Hostname: "b", Type: ProfileEventGauge, Name: "MemoryTrackerUsage", Value: 50},
+	})
+	on.profileEvents([]ProfileEvent{
+		{Hostname: "a", Type: ProfileEventIncrement, Name: "SelectedRows", Value: 7},
+		{Hostname: "a", Type: ProfileEventGauge, Name: "MemoryTrackerUsage", Value: 80},
+	})
+	require.Len(t, updates, 2)
+	assert.Len(t, updates[0].Events, 4)
+	assert.Equal(t, map[string]int64{"SelectedRows": 15, "MemoryTrackerUsage": 150}, updates[0].Totals)
+	assert.Len(t, updates[1].Events, 2)
+	assert.Equal(t, map[string]int64{"SelectedRows": 22, "MemoryTrackerUsage": 130}, updates[1].Totals)
+
+	// totals are per query
+	updates = nil
+	opts.onProcess().profileEvents([]ProfileEvent{
+		{Hostname: "a", Type: ProfileEventIncrement, Name: "SelectedRows", Value: 1},
+	})
+	require.Len(t, updates, 1)
+	assert.Equal(t, map[string]int64{"SelectedRows": 1}, updates[0].Totals)
+}
+
+func TestContextResourceLimits(t *testing.T) {
+	ctx := Context(context.Background(), WithResourceLimits(ResourceLimits{
+		MaxRowsToRead:    1000,
+		MaxResultRows:    10,
+		MaxExecutionTime: 1500 * time.Millisecond,
+	}))
+	opts := queryOptions(ctx)
+	assert.Equal(t, 1000, opts.settings["max_rows_to_read"])
+	assert.Equal(t, 10, opts.settings["max_result_rows"])
+	assert.Equal(t, 2, opts.settings["max_execution_time"])
+	assert.NotContains(t, opts.settings, "max_bytes_to_read")
+
+	h := &httpConnect{url: &url.URL{Scheme: "http", Host: "localhost:8123"}}
+	req, err := h.prepareRequest(ctx, "SELECT 1", &opts, map[string]string{})
+	require.NoError(t, err)
+	query := req.URL.Query()
+	assert.Equal(t, "1000", query.Get("max_rows_to_read"))
+	assert.Equal(t, "10", query.Get("max_result_rows"))
+	assert.Equal(t, "2", query.Get("max_execution_time"))
+	assert.False(t, query.Has("max_bytes_to_read"))
+
+	// the limit is kept when lower than the context deadline, which applies otherwise
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	assert.Equal(t, 2, queryOptions(deadlineCtx).settings["max_execution_time"])
+	longCtx := Context(deadlineCtx, WithResourceLimits(ResourceLimits{MaxExecutionTime: time.Hour}))
+	assert.Less(t, queryOptions(longCtx).settings["max_execution_time"], 3600)
+
+	// a query with a shorter deadline does not lower the limit of the stored context
+	hourCtx := Context(context.Background(), WithResourceLimits(ResourceLimits{MaxExecutionTime: time.Hour}))
+	shortCtx, cancelShort := context.WithTimeout(hourCtx, 10*time.Second)
+	defer cancelShort()
+	assert.Less(t, queryOptions(shortCtx).settings["max_execution_time"], 16)
+	assert.Equal(t, 3600, queryOptions(hourCtx).settings["max_execution_time"])
+
+	var o QueryOptions
+	assert.Error(t, WithResourceLimits(ResourceLimits{MaxExecutionTime: -time.Second})(&o))
+	assert.Error(t, WithResourceLimits(ResourceLimits{MaxBytesToRead: math.MaxUint64})(&o))
+	assert.Nil(t, o.settings)
+
+	// invalid limits fail the query instead of running it without them
+	invalidCtx := Context(context.Background(), WithResourceLimits(ResourceLimits{MaxRowsToRead: 10, MaxExecutionTime: -time.Second}))
+	invalid := queryOptions(invalidCtx)
+	assert.NotContains(t, invalid.settings, "max_rows_to_read")
+	_, err = h.prepareRequest(invalidCtx, "SELECT 1", &invalid, map[string]string{})
+	assert.ErrorContains(t, err, "invalid MaxExecutionTime")
+	assert.Error(t, queryOptions(Context(invalidCtx, WithQueryID("a"))).err)
+
+	// before a connection is acquired, the address is never dialed
+	conn, err := Open(&Options{Addr: []string{"127.0.0.1:1"}})
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.ErrorContains(t, conn.Exec(invalidCtx, "SELECT 1"), "invalid MaxExecutionTime")
+	_, err = conn.Query(invalidCtx, "SELECT 1")
+	assert.ErrorContains(t, err, "invalid MaxExecutionTime")
+}
+
+func TestContextInsertAck(t *testing.T) {
+	var acks []InsertAck
+	ctx := Context(context.Background(), WithInsertAck(func(ack *InsertAck) {
+		acks = append(acks, *ack)
+	}))
+	opts := queryOptions(ctx)
+	// synchronous inserts are acknowledged at the end of the query on the native protocol
+	assert.True(t, opts.acknowledgesInsert(nil, false))
+	assert.False(t, opts.acknowledgesInsert(nil, true))
+	assert.True(t, opts.acknowledgesInsert(Settings{"wait_end_of_query": 1}, true))
+	async := queryOptions(Context(ctx, WithSettings(Settings{"async_insert": 1})))
+	assert.False(t, async.acknowledgesInsert(nil, false))
+	assert.False(t, async.acknowledgesInsert(Settings{"wait_end_of_query": 1}, true))
+	assert.True(t, async.acknowledgesInsert(Settings{"wait_for_async_insert": "true"}, false))
+	// query settings take precedence
+	disabled := queryOptions(Context(ctx, WithSettings(Settings{"wait_end_of_query": 0})))
+	assert.False(t, disabled.acknowledgesInsert(Settings{"wait_end_of_query": 1}, true))
+
+	var (
+		ack InsertAck
+		on  = opts.onProcess()
+	)
+	trackInsertAck(on, &ack)
+	on.progress(&Progress{WroteRows: 2, WroteBytes: 16})
+	on.progress(&Progress{WroteRows: 1, WroteBytes: 8})
+	assert.Equal(t, InsertAck{WrittenRows: 3, WrittenBytes: 24}, ack)
+
+	h := &httpConnect{opt: &Options{Settings: Settings{"wait_end_of_query": 1}}}
+	res := &http.Response{Header: http.Header{}}
+	res.Header.Set("X-ClickHouse-Summary", `{"read_rows":"0","read_bytes":"0","written_rows":"5","written_bytes":"40","total_rows_to_read":"0"}`)
+	h.insertAck(res, &opts)
+	assert.Equal(t, []InsertAck{{WrittenRows: 5, WrittenBytes: 40}}, acks)
+
+	// the insert succeeded without a summary, so it is acknowledged with an unknown count
+	res.Header.Del("X-ClickHouse-Summary")
+	h.insertAck(res, &opts)
+	require.Len(t, acks, 2)
+	assert.Equal(t, InsertAck{Unknown: true}, acks[1])
+	h.opt.Settings = nil
+	h.insertAck(res, &opts)
+	assert.Len(t, acks, 2)
+}
+
+func TestContextSpanPropagation(t *testing.T) {
+	state, err := trace.ParseTraceState("vendor=value")
+	require.NoError(t, err)
+	active := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: state,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), active)
+	opts := queryOptions(ctx)
+	assert.Equal(t, active, opts.spanContext(ctx))
+
+	h := &httpConnect{url: &url.URL{Scheme: "http", Host: "localhost:8123"}}
+	req, err := h.prepareRequest(ctx, "SELECT 1", &opts, map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", req.Header.Get("traceparent"))
+	assert.Equal(t, "vendor=value", req.Header.Get("tracestate"))
+
+	// WithSpan takes precedence over the active span
+	explicit := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	explicitCtx := Context(ctx, WithSpan(explicit))
+	explicitOpts := queryOptions(explicitCtx)
+	assert.Equal(t, explicit, explicitOpts.spanContext(explicitCtx))
+
+	// the span active when the query runs is sent, not the one active when Context was called
+	parent := Context(ctx, WithQueryID("a"))
+	child := trace.ContextWithSpanContext(parent, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: active.TraceID(),
+		SpanID:  trace.SpanID{3},
+	}))
+	childOpts := queryOptions(child)
+	req, err = h.prepareRequest(child, "SELECT 1", &childOpts, map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-0300000000000000-00", req.Header.Get("traceparent"))
+
+	noSpan := queryOptions(context.Background())
+	req, err = h.prepareRequest(context.Background(), "SELECT 1", &noSpan, map[string]string{})
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("traceparent"))
+}
+
+func TestContextProgressChanClosedOnError(t *testing.T) {
+	conn, err := Open(&Options{
+		Addr:        []string{"127.0.0.1:1"},
+		DialTimeout: time.Second,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	queries := map[string]func(ctx context.Context) error{
+		"Query": func(ctx context.Context) error {
+			_, err := conn.Query(ctx, "SELECT 1")
+			return err
+		},
+		"QueryRow": func(ctx context.Context) error {
+			return conn.QueryRow(ctx, "SELECT 1").Err()
+		},
+		"Exec": func(ctx context.Context) error {
+			return conn.Exec(ctx, "SELECT 1")
+		},
+		"PrepareBatch": func(ctx context.Context) error {
+			_, err := conn.PrepareBatch(ctx, "INSERT INTO t")
+			return err
+		},
+		"AsyncInsert": func(ctx context.Context) error {
+			return conn.AsyncInsert(ctx, "INSERT INTO t VALUES (1)", true)
+		},
+	}
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			progress := make(chan Progress, 1)
+			require.Error(t, query(Context(context.Background(), WithProgressChan(progress))))
+			select {
+			case _, ok := <-progress:
+				assert.False(t, ok)
+			case <-time.After(time.Second):
+				t.Fatal("progress channel is not closed")
+			}
+		})
+	}
+}
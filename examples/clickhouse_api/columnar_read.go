@@ -0,0 +1,52 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package clickhouse_api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func ColumnarRead() error {
+	conn, err := GetNativeConnection(nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	rows, err := conn.Query(context.Background(), "SELECT number % 10 AS key, sum(number / 3) AS total FROM numbers(1000000) GROUP BY key")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var sum float64
+	// read the result block by block, as typed slices rather than row by row
+	for clickhouse.NextBlock(rows) {
+		totals, err := clickhouse.BlockColumn[float64](rows, "total")
+		if err != nil {
+			return err
+		}
+		// totals points into the block and is only valid until the next call to NextBlock,
+		// clickhouse.BlockColumnCopy returns a copy which can be kept
+		for _, v := range totals {
+			sum += v
+		}
+	}
+	fmt.Printf("Sum: %f\n", sum)
+	return rows.Err()
+}
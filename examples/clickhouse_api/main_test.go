@@ -112,6 +112,10 @@ func TestColumnInsert(t *testing.T) {
 	require.NoError(t, ColumnInsert())
 }
 
+func TestColumnarRead(t *testing.T) {
+	require.NoError(t, ColumnarRead())
+}
+
 func TestConnect(t *testing.T) {
 	require.NoError(t, Connect())
 }
@@ -172,6 +176,8 @@ func TestNested(t *testing.T) {
 
 func TestProgress(t *testing.T) {
 	require.NoError(t, ProgressProfileLogs())
+	require.NoError(t, ProgressChan())
+	require.NoError(t, InsertSelectProgress())
 }
 
 func TestScanStruct(t *testing.T) {
@@ -19,6 +19,7 @@ package clickhouse_api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ClickHouse/clickhouse-go/v2"
 )
@@ -52,3 +53,75 @@ func ProgressProfileLogs() error {
 	rows.Close()
 	return rows.Err()
 }
+
+func ProgressChan() error {
+	conn, err := GetNativeConnection(nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	// progress events are dropped if the channel is full and the channel is closed once the query ends
+	progress := make(chan clickhouse.Progress, 100)
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithProgressChan(progress))
+	done := make(chan uint64)
+	go func() {
+		totalRows := uint64(0)
+		for p := range progress {
+			totalRows += p.Rows
+		}
+		done <- totalRows
+	}()
+
+	rows, err := conn.Query(ctx, "SELECT number from numbers(1000000) LIMIT 1000000")
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	fmt.Printf("Total Rows: %d\n", <-done)
+	return rows.Err()
+}
+
+// InsertSelectProgress shows a bulk load done by the server, e.g. INSERT INTO example SELECT * FROM s3(...).
+// The query is a regular Exec: over the native protocol progress is reported while it runs, the read timeout
+// applies between progress packets and cancelling the context stops it. A context deadline is also sent to the
+// server as max_execution_time. Over HTTP no progress is reported and closing the request does not stop an
+// INSERT on the server, set max_execution_time or use KILL QUERY with the query ID instead.
+func InsertSelectProgress() error {
+	conn, err := GetNativeConnection(nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		conn.Exec(context.Background(), "DROP TABLE insert_select_example")
+	}()
+	conn.Exec(context.Background(), "DROP TABLE IF EXISTS insert_select_example")
+	if err = conn.Exec(context.Background(), `
+		CREATE TABLE insert_select_example (
+			Col1 UInt64
+		) engine=Memory
+	`); err != nil {
+		return err
+	}
+	readRows := uint64(0)
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithProgress(func(p *clickhouse.Progress) {
+		readRows += p.Rows
+	}))
+	// numbers() stands in for s3(...) or url(...)
+	if err = conn.Exec(ctx, "INSERT INTO insert_select_example SELECT number FROM numbers(10000000)"); err != nil {
+		return err
+	}
+	fmt.Printf("Read Rows: %d\n", readRows)
+
+	// cancel the insert as soon as it reports progress
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = clickhouse.Context(ctx, clickhouse.WithProgress(func(p *clickhouse.Progress) {
+		cancel()
+	}))
+	err = conn.Exec(ctx, "INSERT INTO insert_select_example SELECT number FROM system.numbers LIMIT 100000000000")
+	if !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("expected the insert to be cancelled, got %v", err)
+	}
+	return nil
+}
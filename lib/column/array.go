@@ -264,6 +264,16 @@ func (col *Array) WriteStatePrefix(buffer *proto.Buffer) error {
 
 func (col *Array) ScanRow(dest any, row int) error {
 	elem := reflect.Indirect(reflect.ValueOf(dest))
+	if elem.Kind() == reflect.Array {
+		// fixed size arrays of numeric values are filled in place
+		if values, ok := col.numericRow(elem.Type(), row, 0); ok {
+			if values.Len() != elem.Len() {
+				return col.lengthError(elem.Type(), values.Len())
+			}
+			reflect.Copy(elem, values)
+			return nil
+		}
+	}
 	value, err := col.scan(elem.Type(), row)
 	if err != nil {
 		return err
@@ -272,6 +282,34 @@ func (col *Array) ScanRow(dest any, row int) error {
 	return nil
 }
 
+// numericRow returns the values of row when they are numeric values of the element type of sliceType,
+// so that they can be copied at once rather than element by element.
+func (col *Array) numericRow(sliceType reflect.Type, row int, level int) (reflect.Value, bool) {
+	if level != len(col.offsets)-1 {
+		return reflect.Value{}, false
+	}
+	values := reflect.ValueOf(numericValues(col.values))
+	if !values.IsValid() || values.Type().Elem() != sliceType.Elem() {
+		return reflect.Value{}, false
+	}
+	var (
+		offsets = col.offsets[level].values.col
+		end     = offsets.Row(row)
+		start   = uint64(0)
+	)
+	if row > 0 {
+		start = offsets.Row(row - 1)
+	}
+	return values.Slice(int(start), int(end)), true
+}
+
+func (col *Array) lengthError(sliceType reflect.Type, length int) error {
+	return &Error{
+		ColumnType: string(col.chType),
+		Err:        fmt.Errorf("column %s - array of length %d can not be scanned into %s", col.Name(), length, sliceType),
+	}
+}
+
 func (col *Array) scan(sliceType reflect.Type, row int) (reflect.Value, error) {
 	switch col.values.(type) {
 	case *Tuple:
@@ -309,10 +347,20 @@ func (col *Array) scanSlice(sliceType reflect.Type, row int, level int) (reflect
 		rSlice = reflect.MakeSlice(sliceType, 0, int(end-start))
 	case reflect.Slice:
 		rSlice = reflect.MakeSlice(sliceType, 0, int(end-start))
+	case reflect.Array:
+		// fixed size Go arrays are filled in place and must match the length of the row exactly
+		if sliceType.Len() != int(end-start) {
+			return reflect.Value{}, col.lengthError(sliceType, int(end-start))
+		}
+		rSlice = reflect.New(sliceType).Elem()
+		if values, ok := col.numericRow(sliceType, row, level); ok {
+			reflect.Copy(rSlice, values)
+			return rSlice, nil
+		}
 	default:
 		return reflect.Value{}, &Error{
 			ColumnType: fmt.Sprint(sliceType.Kind()),
-			Err:        fmt.Errorf("column %s - needs a slice or any", col.Name()),
+			Err:        fmt.Errorf("column %s - needs a slice, an array or any", col.Name()),
 		}
 	}
 
@@ -365,6 +413,10 @@ func (col *Array) scanSlice(sliceType reflect.Type, row int, level int) (reflect
 				return reflect.Value{}, err
 			}
 		}
+		if rSlice.Kind() == reflect.Array {
+			rSlice.Index(int(i - start)).Set(value)
+			continue
+		}
 		rSlice = reflect.Append(rSlice, value)
 	}
 	return rSlice, nil
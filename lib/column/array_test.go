@@ -0,0 +1,53 @@
+package column
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrayScanFixedSize(t *testing.T) {
+	t.Parallel()
+	col, err := Type("Array(Float32)").Column("col", nil)
+	require.NoError(t, err)
+	require.NoError(t, col.AppendRow([]float32{1, 2, 3}))
+	require.NoError(t, col.AppendRow([]float32{4, 5, 6}))
+	require.NoError(t, col.AppendRow([]float32{7}))
+	decoded := encodeDecode(t, col)
+
+	var dest [3]float32
+	require.NoError(t, decoded.ScanRow(&dest, 1))
+	assert.Equal(t, [3]float32{4, 5, 6}, dest)
+	// the length of the row must match the array
+	assert.Error(t, decoded.ScanRow(&dest, 2))
+
+	// other element types are converted element by element
+	var converted [3]any
+	require.NoError(t, decoded.ScanRow(&converted, 0))
+	assert.Equal(t, [3]any{float32(1), float32(2), float32(3)}, converted)
+
+	nested, err := Type("Array(Array(UInt8))").Column("col", nil)
+	require.NoError(t, err)
+	require.NoError(t, nested.AppendRow([][]uint8{{1, 2}, {3, 4}}))
+	var matrix [2][2]uint8
+	require.NoError(t, encodeDecode(t, nested).ScanRow(&matrix, 0))
+	assert.Equal(t, [2][2]uint8{{1, 2}, {3, 4}}, matrix)
+}
+
+func BenchmarkArrayScanFixedSize(b *testing.B) {
+	col, err := Type("Array(Float32)").Column("col", nil)
+	require.NoError(b, err)
+	embedding := make([]float32, 768)
+	for i := 0; i < 100; i++ {
+		require.NoError(b, col.AppendRow(embedding))
+	}
+	var dest [768]float32
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := col.ScanRow(&dest, i%100); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -94,6 +94,23 @@ func (col *Map) parse(t Type, tz *time.Location) (_ Interface, err error) {
 	}
 }
 
+// Keys returns the column holding the keys of all the rows, see Offsets.
+func (col *Map) Keys() Interface {
+	return col.keys
+}
+
+// Values returns the column holding the values of all the rows, see Offsets.
+func (col *Map) Values() Interface {
+	return col.values
+}
+
+// Offsets returns the end offset of every row in the Keys and Values columns: the entries of row i
+// are at positions offsets[i-1] (0 for the first row) to offsets[i]. The slice shares the memory of
+// the column and must not be modified.
+func (col *Map) Offsets() []int64 {
+	return col.offsets.col
+}
+
 func (col *Map) Type() Type {
 	return col.chType
 }
@@ -25,6 +25,9 @@ import (
 	"time"
 )
 
+// Nullable wraps a column whose values may be null. ClickHouse does not allow Nullable around
+// composite types, Nullable(Array(T)) is not supported, use Array(Nullable(T)) for arrays of
+// nullable elements.
 type Nullable struct {
 	base     Interface
 	nulls    proto.ColUInt8
@@ -115,6 +118,12 @@ func (col *Nullable) ScanRow(dest any, row int) error {
 				*v = nil
 			case **time.Time:
 				*v = nil
+			default:
+				// any other **T (e.g. **decimal.Decimal) must be reset too, otherwise a null row
+				// leaves the value of the previously scanned row in place
+				if rv := reflect.ValueOf(dest); rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Pointer {
+					rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+				}
 			}
 			if scan, ok := dest.(sql.Scanner); ok {
 				return scan.Scan(nil)
@@ -145,8 +154,9 @@ func (col *Nullable) AppendRow(v any) error {
 		rv = reflect.ValueOf(v)
 	}
 
+	var null uint8
 	if v == nil || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
-		col.nulls.Append(1)
+		null = 1
 		// used to detect sql.Null* types
 	} else if val, ok := v.(driver.Valuer); ok {
 		val, err := val.Value()
@@ -154,14 +164,15 @@ func (col *Nullable) AppendRow(v any) error {
 			return err
 		}
 		if val == nil {
-			col.nulls.Append(1)
-		} else {
-			col.nulls.Append(0)
+			null = 1
 		}
-	} else {
-		col.nulls.Append(0)
 	}
-	return col.base.AppendRow(v)
+	// the null map is only appended once the base accepted the value, so that both stay aligned
+	if err := col.base.AppendRow(v); err != nil {
+		return err
+	}
+	col.nulls.Append(null)
+	return nil
 }
 
 func (col *Nullable) Decode(reader *proto.Reader, rows int) error {
@@ -0,0 +1,85 @@
+package column
+
+import (
+	"testing"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullableDecimalRoundTrip(t *testing.T) {
+	t.Parallel()
+	dec := func(s string) *decimal.Decimal {
+		v := decimal.RequireFromString(s)
+		return &v
+	}
+	values := []*decimal.Decimal{
+		dec("-1.2345"),
+		nil,
+		dec("12.5"),
+		nil,
+		nil,
+		dec("-0.0001"),
+		dec("0"),
+		nil,
+		dec("-999.9999"),
+	}
+	for _, chType := range []Type{"Nullable(Decimal(9,4))", "Nullable(Decimal(18,4))", "Nullable(Decimal(38,4))", "Nullable(Decimal(76,4))"} {
+		chType := chType
+		t.Run(string(chType), func(t *testing.T) {
+			t.Parallel()
+			col, err := chType.Column("col", nil)
+			require.NoError(t, err)
+			for _, v := range values {
+				require.NoError(t, col.AppendRow(v))
+			}
+			var buffer proto.Buffer
+			col.Encode(&buffer)
+
+			decoded, err := chType.Column("col", nil)
+			require.NoError(t, err)
+			require.NoError(t, decoded.Decode(proto.NewReader(&buffer), len(values)))
+			require.Equal(t, len(values), decoded.Rows())
+
+			// the same destination is reused for every row, nulls must reset it
+			var dest *decimal.Decimal
+			for i, expected := range values {
+				require.NoError(t, decoded.ScanRow(&dest, i))
+				if expected == nil {
+					assert.Nil(t, dest, "row %d", i)
+					assert.Nil(t, decoded.Row(i, false), "row %d", i)
+					continue
+				}
+				require.NotNil(t, dest, "row %d", i)
+				assert.True(t, expected.Equal(*dest), "row %d: expected %s, got %s", i, expected, dest)
+				assert.Equal(t, int32(-4), dest.Exponent(), "row %d", i)
+			}
+		})
+	}
+}
+
+func TestArrayOfNullableRoundTrip(t *testing.T) {
+	t.Parallel()
+	col, err := Type("Array(Nullable(Int32))").Column("col", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[]*int32", col.ScanType().String())
+	one, two := int32(1), int32(2)
+	rows := [][]*int32{{&one, nil, &two}, {}, {nil}}
+	for _, row := range rows {
+		require.NoError(t, col.AppendRow(row))
+	}
+	// the elements may be null, not the array
+	require.Error(t, col.AppendRow(nil))
+
+	decoded := encodeDecode(t, col)
+	require.Equal(t, len(rows), decoded.Rows())
+	for i, expected := range rows {
+		var dest []*int32
+		require.NoError(t, decoded.ScanRow(&dest, i))
+		assert.Equal(t, expected, dest, "row %d", i)
+	}
+	var whole *[]int32
+	require.Error(t, decoded.ScanRow(&whole, 0))
+}
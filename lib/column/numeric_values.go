@@ -0,0 +1,105 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package column
+
+// Numeric is the set of Go types numeric column values can be read into without conversion.
+type Numeric interface {
+	int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 | float32 | float64
+}
+
+// NumericValues returns the values of col as a []T which shares the memory of the decoded column,
+// so it must not be modified and is only valid as long as the column. ok is false when the values of
+// col are not of type T (e.g. a Float64 column holds float64) or col is Nullable.
+func NumericValues[T Numeric](col Interface) (values []T, ok bool) {
+	values, ok = numericValues(col).([]T)
+	return values, ok
+}
+
+func numericValues(col Interface) any {
+	switch col := col.(type) {
+	case *SimpleAggregateFunction:
+		return numericValues(col.base)
+	case *Float32:
+		return []float32(col.col)
+	case *Float64:
+		return []float64(col.col)
+	case *Int8:
+		return []int8(col.col)
+	case *Int16:
+		return []int16(col.col)
+	case *Int32:
+		return []int32(col.col)
+	case *Int64:
+		return []int64(col.col)
+	case *UInt8:
+		return []uint8(col.col)
+	case *UInt16:
+		return []uint16(col.col)
+	case *UInt32:
+		return []uint32(col.col)
+	case *UInt64:
+		return []uint64(col.col)
+	}
+	return nil
+}
+
+// Scalar is the set of Go types ScalarValues can return.
+type Scalar interface {
+	Numeric | string
+}
+
+// ScalarValues is like NumericValues, but also supports String and LowCardinality columns.
+// String values are copied out of the column with a single allocation for all of them, so they
+// remain valid once the column is released. ok is false when the values of col are not of type T
+// or col is Nullable.
+func ScalarValues[T Scalar](col Interface) (values []T, ok bool) {
+	switch col := col.(type) {
+	case *String:
+		var v any = col.strings()
+		values, ok = v.([]T)
+		return values, ok
+	case *LowCardinality:
+		if col.nullable {
+			return nil, false
+		}
+		dict, ok := ScalarValues[T](col.index)
+		if !ok {
+			return nil, false
+		}
+		values = make([]T, col.Rows())
+		for i := range values {
+			values[i] = dict[col.indexRowNum(i)]
+		}
+		return values, true
+	case *SimpleAggregateFunction:
+		return ScalarValues[T](col.base)
+	}
+	values, ok = numericValues(col).([]T)
+	return values, ok
+}
+
+func (col *String) strings() []string {
+	var (
+		buf    = string(col.col.Buf)
+		values = make([]string, len(col.col.Pos))
+	)
+	for i, p := range col.col.Pos {
+		values[i] = buf[p.Start:p.End]
+	}
+	return values
+}
@@ -0,0 +1,195 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package column
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/ch-go/proto"
+)
+
+// RowBinaryEncoder writes single values of columns in the RowBinary format. RowBinary matches the Native
+// layout for plain types, but composite types are written per value: arrays and maps are prefixed with their
+// size as a varint and nullable values carry their own null flag.
+type RowBinaryEncoder struct {
+	plain map[Interface]*rowBinaryPlain
+}
+
+// rowBinaryPlain holds the Native encoding of a plain column and the offset of every value within it.
+type rowBinaryPlain struct {
+	buf     []byte
+	offsets []int
+}
+
+func NewRowBinaryEncoder() *RowBinaryEncoder {
+	return &RowBinaryEncoder{
+		plain: make(map[Interface]*rowBinaryPlain),
+	}
+}
+
+// Encode appends the row-th value of col to buffer. Columns must not be modified between calls.
+func (e *RowBinaryEncoder) Encode(col Interface, row int, buffer *proto.Buffer) error {
+	switch col := col.(type) {
+	case *Nullable:
+		if col.nulls.Row(row) == 1 {
+			buffer.PutByte(1)
+			return nil
+		}
+		buffer.PutByte(0)
+		return e.Encode(col.base, row, buffer)
+	case *Array:
+		return e.encodeArray(col, 0, row, buffer)
+	case *Map:
+		var start int64
+		if row > 0 {
+			start = col.offsets.col.Row(row - 1)
+		}
+		end := col.offsets.col.Row(row)
+		buffer.PutUVarInt(uint64(end - start))
+		for i := int(start); i < int(end); i++ {
+			if err := e.Encode(col.keys, i, buffer); err != nil {
+				return err
+			}
+			if err := e.Encode(col.values, i, buffer); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *Tuple:
+		for _, c := range col.columns {
+			if err := e.Encode(c, row, buffer); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *LowCardinality:
+		var idx int
+		switch {
+		case row < len(col.append.keys):
+			// values appended by the client are only turned into keys on Encode
+			idx = col.append.keys[row]
+		default:
+			idx = col.indexRowNum(row)
+		}
+		index := col.index
+		if nullable, ok := index.(*Nullable); ok {
+			if idx == 0 {
+				buffer.PutByte(1)
+				return nil
+			}
+			buffer.PutByte(0)
+			index = nullable.base
+		}
+		return e.Encode(index, idx, buffer)
+	case *Nested:
+		return e.Encode(col.Interface, row, buffer)
+	case *SimpleAggregateFunction:
+		return e.Encode(col.base, row, buffer)
+	case *Ring:
+		return e.Encode(col.set, row, buffer)
+	case *Polygon:
+		return e.Encode(col.set, row, buffer)
+	case *MultiPolygon:
+		return e.Encode(col.set, row, buffer)
+	case *Point:
+		buffer.PutFloat64(col.col.X.Row(row))
+		buffer.PutFloat64(col.col.Y.Row(row))
+		return nil
+	case *JSONObject:
+		return &Error{
+			ColumnType: string(col.Type()),
+			Err:        errors.New("RowBinary encoding is not supported"),
+		}
+	}
+	return e.encodePlain(col, row, buffer)
+}
+
+func (e *RowBinaryEncoder) encodeArray(col *Array, level, row int, buffer *proto.Buffer) error {
+	offset := col.offsets[level]
+	var start uint64
+	if row > 0 {
+		start = offset.values.col.Row(row - 1)
+	}
+	end := offset.values.col.Row(row)
+	buffer.PutUVarInt(end - start)
+	for i := int(start); i < int(end); i++ {
+		var err error
+		if level < len(col.offsets)-1 {
+			err = e.encodeArray(col, level+1, i, buffer)
+		} else {
+			err = e.Encode(col.values, i, buffer)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *RowBinaryEncoder) encodePlain(col Interface, row int, buffer *proto.Buffer) error {
+	plain, ok := e.plain[col]
+	if !ok {
+		var (
+			b    proto.Buffer
+			rows = col.Rows()
+		)
+		col.Encode(&b)
+		plain = &rowBinaryPlain{
+			buf:     b.Buf,
+			offsets: make([]int, 0, rows+1),
+		}
+		switch col.(type) {
+		case *String:
+			// values are prefixed with their length
+			for pos := 0; len(plain.offsets) < rows; {
+				plain.offsets = append(plain.offsets, pos)
+				n, size := binary.Uvarint(b.Buf[pos:])
+				if size <= 0 {
+					return &Error{
+						ColumnType: string(col.Type()),
+						Err:        errors.New("invalid string length"),
+					}
+				}
+				pos += size + int(n)
+			}
+			plain.offsets = append(plain.offsets, len(b.Buf))
+		default:
+			if rows == 0 || len(b.Buf)%rows != 0 {
+				return &Error{
+					ColumnType: string(col.Type()),
+					Err:        fmt.Errorf("unexpected Native encoding of %d bytes for %d rows", len(b.Buf), rows),
+				}
+			}
+			size := len(b.Buf) / rows
+			for i := 0; i <= rows; i++ {
+				plain.offsets = append(plain.offsets, i*size)
+			}
+		}
+		e.plain[col] = plain
+	}
+	if row >= len(plain.offsets)-1 {
+		return &Error{
+			ColumnType: string(col.Type()),
+			Err:        fmt.Errorf("row %d is out of range", row),
+		}
+	}
+	buffer.PutRaw(plain.buf[plain.offsets[row]:plain.offsets[row+1]])
+	return nil
+}
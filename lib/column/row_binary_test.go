@@ -0,0 +1,110 @@
+package column
+
+import (
+	"testing"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowBinaryEncoder(t *testing.T) {
+	t.Parallel()
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name     string
+		chType   Type
+		rows     []any
+		expected [][]byte
+	}{
+		{
+			name:   "plain",
+			chType: "UInt16",
+			rows:   []any{uint16(1), uint16(0x0203)},
+			expected: [][]byte{
+				{0x01, 0x00},
+				{0x03, 0x02},
+			},
+		},
+		{
+			name:   "string",
+			chType: "String",
+			rows:   []any{"ab", "", "c"},
+			expected: [][]byte{
+				{0x02, 'a', 'b'},
+				{0x00},
+				{0x01, 'c'},
+			},
+		},
+		{
+			name:   "nullable",
+			chType: "Nullable(Int8)",
+			rows:   []any{nil, int8(-1)},
+			expected: [][]byte{
+				{0x01},
+				{0x00, 0xff},
+			},
+		},
+		{
+			name:   "array of nullable",
+			chType: "Array(Nullable(String))",
+			rows:   []any{[]*string{str("a"), nil}, []*string{}},
+			expected: [][]byte{
+				{0x02, 0x00, 0x01, 'a', 0x01},
+				{0x00},
+			},
+		},
+		{
+			name:   "nested arrays",
+			chType: "Array(Array(UInt8))",
+			rows:   []any{[][]uint8{{1}, {2, 3}}},
+			expected: [][]byte{
+				{0x02, 0x01, 0x01, 0x02, 0x02, 0x03},
+			},
+		},
+		{
+			name:   "map",
+			chType: "Map(String, UInt8)",
+			rows:   []any{map[string]uint8{"a": 1}},
+			expected: [][]byte{
+				{0x01, 0x01, 'a', 0x01},
+			},
+		},
+		{
+			name:   "tuple",
+			chType: "Tuple(String, UInt8)",
+			rows:   []any{[]any{"a", uint8(1)}, []any{"bc", uint8(2)}},
+			expected: [][]byte{
+				{0x01, 'a', 0x01},
+				{0x02, 'b', 'c', 0x02},
+			},
+		},
+		{
+			name:   "low cardinality",
+			chType: "LowCardinality(Nullable(String))",
+			rows:   []any{"a", nil, "a"},
+			expected: [][]byte{
+				{0x00, 0x01, 'a'},
+				{0x01},
+				{0x00, 0x01, 'a'},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			col, err := test.chType.Column("col", nil)
+			require.NoError(t, err)
+			for _, row := range test.rows {
+				require.NoError(t, col.AppendRow(row))
+			}
+			encoder := NewRowBinaryEncoder()
+			for row, expected := range test.expected {
+				var buffer proto.Buffer
+				require.NoError(t, encoder.Encode(col, row, &buffer))
+				assert.Equal(t, expected, buffer.Buf)
+			}
+		})
+	}
+}
@@ -24,10 +24,15 @@ import (
 	"fmt"
 	"github.com/ClickHouse/ch-go/proto"
 	"reflect"
+	"strings"
+	"unicode"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
 )
 
+// String is decoded byte-exact: values are never trimmed, normalized or validated as UTF-8,
+// so trailing whitespace and embedded null bytes are returned exactly as stored.
+// Scan into a TrimmedString to opt in to trimming.
 type String struct {
 	name string
 	col  proto.ColStr
@@ -208,3 +213,30 @@ func (col *String) Encode(buffer *proto.Buffer) {
 }
 
 var _ Interface = (*String)(nil)
+
+// TrimmedString is an opt-in scan target for String columns which removes trailing
+// whitespace (as defined by unicode.IsSpace) from the scanned value. Null bytes are not whitespace
+// and are kept. A NULL value scans as an empty string.
+type TrimmedString string
+
+func (s *TrimmedString) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*s = TrimmedString(strings.TrimRightFunc(v, unicode.IsSpace))
+	case []byte:
+		*s = TrimmedString(strings.TrimRightFunc(string(v), unicode.IsSpace))
+	case nil:
+		*s = ""
+	default:
+		return &ColumnConverterError{
+			Op:   "Scan",
+			To:   "TrimmedString",
+			From: fmt.Sprintf("%T", src),
+		}
+	}
+	return nil
+}
+
+func (s TrimmedString) Value() (driver.Value, error) {
+	return string(s), nil
+}
@@ -0,0 +1,85 @@
+package column
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringByteFidelity(t *testing.T) {
+	t.Parallel()
+	values := []string{
+		"trailing  ",
+		"  leading",
+		"embedded\x00null",
+		"trailing null\x00\x00",
+		"mixed \x00 \t\n",
+		"\x00",
+		" ",
+		"",
+		"\xff\xfe not utf-8 ",
+	}
+	col, err := Type("String").Column("col", nil)
+	require.NoError(t, err)
+	for _, v := range values {
+		require.NoError(t, col.AppendRow(v))
+	}
+	var buffer proto.Buffer
+	col.Encode(&buffer)
+
+	decoded, err := Type("String").Column("col", nil)
+	require.NoError(t, err)
+	require.NoError(t, decoded.Decode(proto.NewReader(&buffer), len(values)))
+
+	for i, expected := range values {
+		var (
+			str  string
+			ptr  *string
+			null sql.NullString
+		)
+		require.NoError(t, decoded.ScanRow(&str, i))
+		require.NoError(t, decoded.ScanRow(&ptr, i))
+		require.NoError(t, decoded.ScanRow(&null, i))
+		assert.Equal(t, expected, str, "row %d", i)
+		assert.Equal(t, expected, *ptr, "row %d", i)
+		assert.Equal(t, expected, null.String, "row %d", i)
+		assert.Equal(t, expected, decoded.Row(i, false), "row %d", i)
+		assert.Equal(t, []byte(expected), []byte(str), "row %d", i)
+	}
+}
+
+func TestTrimmedString(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"trailing  ":              "trailing",
+		"  leading":               "  leading",
+		"tabs and newlines\t\r\n": "tabs and newlines",
+		"embedded\x00null ":       "embedded\x00null",
+		"trailing null\x00":       "trailing null\x00",
+		"null then space\x00 ":    "null then space\x00",
+		"   ":                     "",
+		"":                        "",
+	}
+	col, err := Type("String").Column("col", nil)
+	require.NoError(t, err)
+	var inputs []string
+	for input := range tests {
+		inputs = append(inputs, input)
+		require.NoError(t, col.AppendRow(input))
+	}
+	for i, input := range inputs {
+		var trimmed TrimmedString
+		require.NoError(t, col.ScanRow(&trimmed, i))
+		assert.Equal(t, TrimmedString(tests[input]), trimmed, "%q", input)
+	}
+
+	var trimmed TrimmedString = "x"
+	require.NoError(t, trimmed.Scan(nil))
+	assert.Equal(t, TrimmedString(""), trimmed)
+	require.NoError(t, trimmed.Scan([]byte("bytes \n")))
+	assert.Equal(t, TrimmedString("bytes"), trimmed)
+	assert.Error(t, trimmed.Scan(1))
+}
@@ -243,6 +243,58 @@ func getStructFieldValue(field reflect.Value, name string) (reflect.Value, bool)
 	return sField, sField.IsValid()
 }
 
+// structField returns the field of targetStruct for the sub column i of the tuple, by tag or name, see
+// getStructFieldValue, and otherwise by name ignoring case, since tuple elements are usually lower case
+// unlike exported struct fields, e.g. Tuple(a UInt64). The case-insensitive match fails when several
+// fields match or when another element matches the same field. An unnamed element, e.g. in
+// groupArray((a, b)), is matched by position among the exported fields.
+func (col *Tuple) structField(targetStruct reflect.Value, i int) (reflect.Value, bool, error) {
+	tStruct := targetStruct.Type()
+	name := col.columns[i].Name()
+	if name == "" {
+		for j, exported := 0, 0; j < tStruct.NumField(); j++ {
+			if !tStruct.Field(j).IsExported() {
+				continue
+			}
+			if exported == i {
+				return targetStruct.Field(j), true, nil
+			}
+			exported++
+		}
+		return reflect.Value{}, false, nil
+	}
+	if sField, ok := getStructFieldValue(targetStruct, name); ok {
+		return sField, true, nil
+	}
+	match := -1
+	for j := 0; j < tStruct.NumField(); j++ {
+		if field := tStruct.Field(j); field.IsExported() && strings.EqualFold(field.Name, name) {
+			if match != -1 {
+				return reflect.Value{}, false, col.ambiguousField(tStruct, name)
+			}
+			match = j
+		}
+	}
+	if match == -1 {
+		return reflect.Value{}, false, nil
+	}
+	field := tStruct.Field(match)
+	for k, c := range col.columns {
+		other := c.Name()
+		if k != i && (strings.EqualFold(other, field.Name) || field.Tag.Get("json") == other || field.Tag.Get("ch") == other) {
+			return reflect.Value{}, false, col.ambiguousField(tStruct, name)
+		}
+	}
+	return targetStruct.Field(match), true, nil
+}
+
+func (col *Tuple) ambiguousField(tStruct reflect.Type, name string) error {
+	return &Error{
+		ColumnType: string(col.chType),
+		Err:        fmt.Errorf("element %s of %s matches several fields of %s ignoring case, use a ch tag", name, col.Name(), tStruct),
+	}
+}
+
 func unescapeColName(colName string) string {
 	s := []rune(colName)
 	if s[0:1][0] == '`' && s[len(s)-1:][0] == '`' {
@@ -326,9 +378,12 @@ func (col *Tuple) scanMap(targetMap reflect.Value, row int) error {
 }
 
 func (col *Tuple) scanStruct(targetStruct reflect.Value, row int) error {
-	for _, c := range col.columns {
+	for i, c := range col.columns {
 		// the column may be serialized using a different name due to a struct "targetStruct" tag
-		sField, ok := getStructFieldValue(targetStruct, c.Name())
+		sField, ok, err := col.structField(targetStruct, i)
+		if err != nil {
+			return err
+		}
 		// test if map
 		if !ok {
 			continue
@@ -517,6 +572,9 @@ func (col *Tuple) AppendRow(v any) error {
 	if value.Kind() == reflect.Pointer {
 		value = value.Elem()
 	}
+	if _, isValuer := v.(driver.Valuer); !isValuer && value.Kind() == reflect.Struct {
+		return col.appendStruct(value, v)
+	}
 	switch value.Kind() {
 	case reflect.Map:
 		if !col.isNamed {
@@ -586,6 +644,31 @@ func (col *Tuple) AppendRow(v any) error {
 	}
 }
 
+// appendStruct appends the fields of a struct matching the elements of the tuple, see structField.
+func (col *Tuple) appendStruct(value reflect.Value, v any) error {
+	// look up all the fields first, so that a missing one does not leave the sub columns misaligned
+	fields := make([]reflect.Value, len(col.columns))
+	for i := range col.columns {
+		field, ok, err := col.structField(value, i)
+		if err != nil {
+			return err
+		}
+		if !ok || !field.CanInterface() {
+			return &Error{
+				ColumnType: string(col.chType),
+				Err:        fmt.Errorf("%T has no exported field for element %d of %s", v, i, col.Name()),
+			}
+		}
+		fields[i] = field
+	}
+	for i, field := range fields {
+		if err := col.columns[i].AppendRow(field.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (col *Tuple) Decode(reader *proto.Reader, rows int) error {
 	for _, c := range col.columns {
 		if err := c.Decode(reader, rows); err != nil {
@@ -0,0 +1,159 @@
+package column
+
+import (
+	"testing"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeDecode returns a copy of col decoded from its native encoding.
+func encodeDecode(t *testing.T, col Interface) Interface {
+	var buffer proto.Buffer
+	if serialize, ok := col.(CustomSerialization); ok {
+		require.NoError(t, serialize.WriteStatePrefix(&buffer))
+	}
+	col.Encode(&buffer)
+	decoded, err := col.Type().Column(col.Name(), nil)
+	require.NoError(t, err)
+	reader := proto.NewReader(&buffer)
+	if serialize, ok := decoded.(CustomSerialization); ok {
+		require.NoError(t, serialize.ReadStatePrefix(reader))
+	}
+	require.NoError(t, decoded.Decode(reader, col.Rows()))
+	return decoded
+}
+
+func TestArrayOfNamedTuplesStructs(t *testing.T) {
+	t.Parallel()
+	type pair struct {
+		A uint64
+		B string
+	}
+	col, err := Type("Array(Tuple(a UInt64, b String))").Column("col", nil)
+	require.NoError(t, err)
+	rows := [][]pair{
+		{{A: 1, B: "x"}, {A: 2, B: "y"}},
+		{},
+		{{A: 3, B: "z"}},
+	}
+	for _, row := range rows {
+		require.NoError(t, col.AppendRow(row))
+	}
+	decoded := encodeDecode(t, col)
+	for i, expected := range rows {
+		var dest []pair
+		require.NoError(t, decoded.ScanRow(&dest, i))
+		assert.Equal(t, expected, dest, "row %d", i)
+	}
+}
+
+func TestNestedArrayOfNamedTuplesStructs(t *testing.T) {
+	t.Parallel()
+	type inner struct {
+		Key   string
+		Value []int32
+	}
+	type outer struct {
+		ID    uint8
+		Pairs []inner `ch:"pairs"`
+	}
+	tests := []struct {
+		chType Type
+		rows   any
+		dest   func() any
+	}{
+		{
+			chType: "Array(Array(Tuple(key String, value Array(Int32))))",
+			rows: [][][]inner{
+				{{{Key: "a", Value: []int32{1, 2}}}, {}, {{Key: "b", Value: []int32{}}, {Key: "c", Value: []int32{3}}}},
+				{},
+			},
+			dest: func() any { return new([][]inner) },
+		},
+		{
+			chType: "Array(Tuple(id UInt8, pairs Array(Tuple(key String, value Array(Int32)))))",
+			rows: [][]outer{
+				{{ID: 1, Pairs: []inner{{Key: "a", Value: []int32{1}}}}, {ID: 2, Pairs: []inner{}}},
+				{{ID: 3, Pairs: []inner{{Key: "b", Value: []int32{}}, {Key: "c", Value: []int32{4, 5}}}}},
+			},
+			dest: func() any { return new([]outer) },
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.chType), func(t *testing.T) {
+			t.Parallel()
+			col, err := test.chType.Column("col", nil)
+			require.NoError(t, err)
+			_, err = col.Append(test.rows)
+			require.NoError(t, err)
+			decoded := encodeDecode(t, col)
+			var scanned []any
+			for i := 0; i < decoded.Rows(); i++ {
+				dest := test.dest()
+				require.NoError(t, decoded.ScanRow(dest, i))
+				scanned = append(scanned, dest)
+			}
+			switch rows := test.rows.(type) {
+			case [][][]inner:
+				for i := range rows {
+					assert.Equal(t, rows[i], *scanned[i].(*[][]inner), "row %d", i)
+				}
+			case [][]outer:
+				for i := range rows {
+					assert.Equal(t, rows[i], *scanned[i].(*[]outer), "row %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestTupleAppendStruct(t *testing.T) {
+	t.Parallel()
+	col, err := Type("Tuple(a UInt64, b String)").Column("col", nil)
+	require.NoError(t, err)
+	require.Error(t, col.AppendRow(struct{ A uint64 }{A: 1}))
+	assert.Equal(t, 0, col.Rows())
+	require.NoError(t, col.AppendRow(&struct {
+		A     uint64
+		Other string `ch:"b"`
+	}{A: 1, Other: "x"}))
+
+	// unnamed elements match the exported fields by position
+	type positional struct {
+		ID     uint64
+		hidden bool
+		Name   string
+	}
+	unnamed, err := Type("Tuple(UInt64, String)").Column("col", nil)
+	require.NoError(t, err)
+	require.NoError(t, unnamed.AppendRow(positional{ID: 1, Name: "x"}))
+	require.Error(t, unnamed.AppendRow(struct{ ID uint64 }{ID: 2}))
+	var dest positional
+	require.NoError(t, encodeDecode(t, unnamed).ScanRow(&dest, 0))
+	assert.Equal(t, positional{ID: 1, Name: "x"}, dest)
+
+	// matching ignoring case must not be ambiguous
+	named, err := Type("Tuple(name String, id UInt64)").Column("col", nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, named.AppendRow(struct {
+		Name string
+		NAME string
+		ID   uint64
+	}{}), "matches several fields")
+	require.NoError(t, named.AppendRow(struct {
+		Name string
+		NAME string `ch:"name"`
+		ID   uint64
+	}{NAME: "x", ID: 1}))
+	sameField, err := Type("Tuple(name String, Name String)").Column("col", nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, sameField.AppendRow(struct{ NAME string }{}), "matches several fields")
+	require.NoError(t, sameField.AppendRow(struct {
+		Lower string `ch:"name"`
+		Name  string
+	}{Lower: "a", Name: "b"}))
+	assert.Equal(t, 1, sameField.Rows())
+}
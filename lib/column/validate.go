@@ -0,0 +1,256 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package column
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/shopspring/decimal"
+)
+
+// Validate checks that v can be appended to col without being silently altered: nil is only
+// accepted by Nullable columns, integers must be in the range of the column type, Enum values
+// must be members of the enum, Decimal values must fit the precision and scale and FixedString
+// values the size of the column. Arrays, maps and tuples are checked element by element.
+// Values the check has no rule for are accepted, leaving any conversion error to AppendRow.
+func Validate(col Interface, v any) error {
+	if isNull(v) {
+		switch col := col.(type) {
+		case *Nullable, *Nothing:
+			return nil
+		case *LowCardinality:
+			if col.nullable {
+				return nil
+			}
+		}
+		return errors.New("nil value for a column which is not Nullable")
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return Validate(col, nil)
+		}
+		rv = rv.Elem()
+	}
+	switch col := col.(type) {
+	case *Nullable:
+		return Validate(col.base, v)
+	case *LowCardinality:
+		return Validate(col.index, v)
+	case *SimpleAggregateFunction:
+		return Validate(col.base, v)
+	case *Int8:
+		return validateInt(rv, math.MinInt8, math.MaxInt8)
+	case *Int16:
+		return validateInt(rv, math.MinInt16, math.MaxInt16)
+	case *Int32:
+		return validateInt(rv, math.MinInt32, math.MaxInt32)
+	case *Int64:
+		return validateInt(rv, math.MinInt64, math.MaxInt64)
+	case *UInt8:
+		return validateUInt(rv, math.MaxUint8)
+	case *UInt16:
+		return validateUInt(rv, math.MaxUint16)
+	case *UInt32:
+		return validateUInt(rv, math.MaxUint32)
+	case *UInt64:
+		return validateUInt(rv, math.MaxUint64)
+	case *Float32:
+		if isFloat(rv) {
+			if f := rv.Float(); !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+				return fmt.Errorf("value %v is out of range", f)
+			}
+		}
+	case *Enum8:
+		return validateEnum(rv, func(s string) bool {
+			_, ok := col.iv[s]
+			return ok
+		}, func(i int64) bool {
+			_, ok := col.vi[proto.Enum8(i)]
+			return ok && i >= math.MinInt8 && i <= math.MaxInt8
+		})
+	case *Enum16:
+		return validateEnum(rv, func(s string) bool {
+			_, ok := col.iv[s]
+			return ok
+		}, func(i int64) bool {
+			_, ok := col.vi[proto.Enum16(i)]
+			return ok && i >= math.MinInt16 && i <= math.MaxInt16
+		})
+	case *Decimal:
+		if d, ok := rv.Interface().(decimal.Decimal); ok {
+			if !d.Equal(d.Truncate(int32(col.scale))) {
+				return fmt.Errorf("value %s has more than %d decimal places", d, col.scale)
+			}
+			if d.Abs().Cmp(decimal.New(1, int32(col.precision-col.scale))) >= 0 {
+				return fmt.Errorf("value %s is out of range", d)
+			}
+		}
+	case *FixedString:
+		var size int
+		switch {
+		case rv.Kind() == reflect.String:
+			size = rv.Len()
+		case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+			size = rv.Len()
+		}
+		if size > col.col.Size {
+			return fmt.Errorf("value of %d bytes exceeds the column size", size)
+		}
+	case *Array:
+		return validateArray(col.values, rv, col.depth)
+	case *Map:
+		if rv.Kind() != reflect.Map {
+			return nil
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := Validate(col.keys, iter.Key().Interface()); err != nil {
+				return fmt.Errorf("key %v: %w", iter.Key(), err)
+			}
+			if err := Validate(col.values, iter.Value().Interface()); err != nil {
+				return fmt.Errorf("value of key %v: %w", iter.Key(), err)
+			}
+		}
+	case *Tuple:
+		if rv.Kind() == reflect.Struct {
+			for i, c := range col.columns {
+				field, ok, err := col.structField(rv, i)
+				if err != nil {
+					return err
+				}
+				if !ok || !field.CanInterface() {
+					// reported by AppendRow
+					return nil
+				}
+				if err := Validate(c, field.Interface()); err != nil {
+					return fmt.Errorf("element %d: %w", i, err)
+				}
+			}
+			return nil
+		}
+		if rv.Kind() != reflect.Slice || rv.Len() != len(col.columns) {
+			return nil
+		}
+		for i, c := range col.columns {
+			if err := Validate(c, rv.Index(i).Interface()); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func isNull(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return true
+	}
+	// sql.Null* types
+	if valuer, ok := v.(driver.Valuer); ok {
+		if val, err := valuer.Value(); err == nil && val == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isFloat(rv reflect.Value) bool {
+	return rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64
+}
+
+func validateInt(rv reflect.Value, min, max int64) error {
+	switch {
+	case rv.CanInt():
+		if i := rv.Int(); i < min || i > max {
+			return fmt.Errorf("value %d is out of range", i)
+		}
+	case rv.CanUint():
+		if u := rv.Uint(); u > uint64(max) {
+			return fmt.Errorf("value %d is out of range", u)
+		}
+	case isFloat(rv):
+		// max+1 is a power of two, exact as a float64 unlike max itself for 64 bit types
+		if f := rv.Float(); f != math.Trunc(f) || f < float64(min) || f >= float64(max)+1 {
+			return fmt.Errorf("value %v is not an integer in range", f)
+		}
+	}
+	return nil
+}
+
+func validateUInt(rv reflect.Value, max uint64) error {
+	switch {
+	case rv.CanInt():
+		if i := rv.Int(); i < 0 || uint64(i) > max {
+			return fmt.Errorf("value %d is out of range", i)
+		}
+	case rv.CanUint():
+		if u := rv.Uint(); u > max {
+			return fmt.Errorf("value %d is out of range", u)
+		}
+	case isFloat(rv):
+		if f := rv.Float(); f != math.Trunc(f) || f < 0 || f >= float64(max)+1 {
+			return fmt.Errorf("value %v is not an integer in range", f)
+		}
+	}
+	return nil
+}
+
+func validateEnum(rv reflect.Value, hasName func(string) bool, hasValue func(int64) bool) error {
+	switch {
+	case rv.Kind() == reflect.String:
+		if !hasName(rv.String()) {
+			return fmt.Errorf("unknown element %q", rv.String())
+		}
+	case rv.CanInt():
+		if !hasValue(rv.Int()) {
+			return fmt.Errorf("unknown element %d", rv.Int())
+		}
+	}
+	return nil
+}
+
+func validateArray(values Interface, rv reflect.Value, depth int) error {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	for i := 0; i < rv.Len(); i++ {
+		var err error
+		switch elem := rv.Index(i); {
+		case depth > 1:
+			for elem.Kind() == reflect.Pointer && !elem.IsNil() {
+				elem = elem.Elem()
+			}
+			err = validateArray(values, elem, depth-1)
+		default:
+			err = Validate(values, elem.Interface())
+		}
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return nil
+}
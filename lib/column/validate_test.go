@@ -0,0 +1,82 @@
+package column
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	str := func(s string) *string { return &s }
+	var nilStr *string
+	tests := []struct {
+		chType Type
+		value  any
+		err    string
+	}{
+		{chType: "UInt8", value: uint8(255)},
+		{chType: "UInt8", value: 255},
+		{chType: "UInt8", value: 256, err: "value 256 is out of range"},
+		{chType: "UInt8", value: -1, err: "value -1 is out of range"},
+		{chType: "UInt8", value: 1.5, err: "value 1.5 is not an integer in range"},
+		{chType: "UInt64", value: uint64(1 << 63)},
+		{chType: "Int8", value: int64(-128)},
+		{chType: "Int8", value: int64(-129), err: "value -129 is out of range"},
+		{chType: "Int64", value: uint64(1 << 63), err: "value 9223372036854775808 is out of range"},
+		{chType: "Int64", value: float64(1 << 63), err: "value 9.223372036854776e+18 is not an integer in range"},
+		{chType: "Int64", value: -float64(1 << 63)},
+		{chType: "UInt64", value: math.Pow(2, 64), err: "value 1.8446744073709552e+19 is not an integer in range"},
+		{chType: "UInt64", value: float64(1 << 63)},
+		{chType: "Int8", value: 127.0},
+		{chType: "Int8", value: 128.0, err: "value 128 is not an integer in range"},
+		{chType: "Float32", value: 1e39, err: "value 1e+39 is out of range"},
+		{chType: "Float32", value: 1.5},
+		{chType: "String", value: nil, err: "nil value for a column which is not Nullable"},
+		{chType: "String", value: nilStr, err: "nil value for a column which is not Nullable"},
+		{chType: "UInt8", value: sql.NullInt32{}, err: "nil value for a column which is not Nullable"},
+		{chType: "Nullable(String)", value: nil},
+		{chType: "Nullable(String)", value: nilStr},
+		{chType: "Nullable(String)", value: &nilStr},
+		{chType: "Nullable(UInt8)", value: str("x")},
+		{chType: "Nullable(UInt8)", value: 300, err: "value 300 is out of range"},
+		{chType: "LowCardinality(Nullable(String))", value: nil},
+		{chType: "LowCardinality(String)", value: nil, err: "nil value for a column which is not Nullable"},
+		{chType: "Enum8('a' = 1, 'b' = 2)", value: "a"},
+		{chType: "Enum8('a' = 1, 'b' = 2)", value: "c", err: `unknown element "c"`},
+		{chType: "Enum8('a' = 1, 'b' = 2)", value: 2},
+		{chType: "Enum8('a' = 1, 'b' = 2)", value: 3, err: "unknown element 3"},
+		{chType: "Enum8('a' = 1, 'b' = 2)", value: 257, err: "unknown element 257"},
+		{chType: "Enum16('a' = 1000)", value: str("a")},
+		{chType: "Decimal(9,2)", value: decimal.RequireFromString("-9999999.99")},
+		{chType: "Decimal(9,2)", value: decimal.RequireFromString("10000000"), err: "value 10000000 is out of range"},
+		{chType: "Decimal(9,2)", value: decimal.RequireFromString("1.234"), err: "value 1.234 has more than 2 decimal places"},
+		{chType: "FixedString(2)", value: "ab"},
+		{chType: "FixedString(2)", value: []byte("abc"), err: "value of 3 bytes exceeds the column size"},
+		{chType: "Array(UInt8)", value: []int{1, 2}},
+		{chType: "Array(UInt8)", value: []int{1, 256}, err: "element 1: value 256 is out of range"},
+		{chType: "Array(Array(Nullable(Int8)))", value: [][]*int8{{nil}, {}}},
+		{chType: "Array(Array(String))", value: [][]*string{{}, {str("a"), nil}}, err: "element 1: element 1: nil value for a column which is not Nullable"},
+		{chType: "Map(String, UInt8)", value: map[string]int{"a": 1}},
+		{chType: "Map(String, UInt8)", value: map[string]int{"a": 1000}, err: "value of key a: value 1000 is out of range"},
+		{chType: "Tuple(String, Int8)", value: []any{"a", 1}},
+		{chType: "Tuple(String, Int8)", value: []any{"a", 128}, err: "element 1: value 128 is out of range"},
+		{chType: "UInt8", value: "not a number"},
+	}
+	for _, test := range tests {
+		col, err := test.chType.Column("col", nil)
+		require.NoError(t, err)
+		err = Validate(col, test.value)
+		if test.err == "" {
+			assert.NoError(t, err, "%s %#v", test.chType, test.value)
+			continue
+		}
+		if assert.Error(t, err, "%s %#v", test.chType, test.value) {
+			assert.Equal(t, test.err, err.Error(), "%s %#v", test.chType, test.value)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package driver
 
 type PrepareBatchOptions struct {
-	ReleaseConnection bool
+	ReleaseConnection     bool
+	RowBinaryWithDefaults bool // HTTP only, set for database/sql by clickhouse.WithStdRowBinaryWithDefaults
+	StrictTypeCheck       bool
+	OmitDefaultColumns    bool
 }
 
 type PrepareBatchOption func(options *PrepareBatchOptions)
@@ -11,3 +14,23 @@ func WithReleaseConnection() PrepareBatchOption {
 		options.ReleaseConnection = true
 	}
 }
+
+// WithStrictTypeCheck validates every appended value against the type of its column before it is
+// added to the batch, see column.Validate. A value which is out of range, nil for a column which is
+// not Nullable, not a member of an Enum or which would otherwise be silently altered makes Append return
+// an error with the row and column of the value. The row is not appended and the batch remains usable.
+func WithStrictTypeCheck() PrepareBatchOption {
+	return func(options *PrepareBatchOptions) {
+		options.StrictTypeCheck = true
+	}
+}
+
+// WithOmitDefaultColumns checks that every column of the table which is not in the column list of the
+// INSERT query has a DEFAULT, MATERIALIZED, ALIAS or EPHEMERAL expression, so that the server fills the
+// omitted columns from their expressions. PrepareBatch returns an error naming the first omitted column
+// without one, instead of the server silently inserting the zero value of its type.
+func WithOmitDefaultColumns() PrepareBatchOption {
+	return func(options *PrepareBatchOptions) {
+		options.OmitDefaultColumns = true
+	}
+}
@@ -19,10 +19,12 @@ package clickhouse
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
@@ -70,6 +72,48 @@ func (ch *clickhouse) Select(ctx context.Context, dest any, query string, args .
 	return rows.Err()
 }
 
+// QueryScalar runs a query returning a single value on conn, e.g. SELECT count(), and scans it into dest.
+// It returns sql.ErrNoRows if the query returns no row, and an error if it returns more than one column or row.
+func QueryScalar(ctx context.Context, conn driver.Conn, dest any, query string, args ...any) error {
+	// the query is cancelled, rather than read to the end, when it returns more than one row
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if columns := rows.Columns(); len(columns) != 1 {
+		cancel()
+		return &OpError{
+			Op:  "QueryScalar",
+			Err: fmt.Errorf("expected a single column, got %d", len(columns)),
+		}
+	}
+	if !rows.Next() {
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := rows.Scan(dest); err != nil {
+		cancel()
+		return err
+	}
+	if rows.Next() {
+		cancel()
+		return &OpError{
+			Op:  "QueryScalar",
+			Err: errors.New("expected a single row, got more"),
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
 func scan(block *proto.Block, row int, dest ...any) error {
 	columns := block.Columns
 	if len(columns) != len(dest) {
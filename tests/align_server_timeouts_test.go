@@ -0,0 +1,58 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignServerTimeouts(t *testing.T) {
+	te, err := GetTestEnvironment(testSet)
+	require.NoError(t, err)
+	opts := ClientOptionsFromEnv(te, clickhouse.Settings{})
+	useSSL, err := strconv.ParseBool(GetEnv("CLICKHOUSE_USE_SSL", "false"))
+	require.NoError(t, err)
+	port := te.HttpPort
+	if useSSL {
+		port = te.HttpsPort
+	}
+	opts.Addr = []string{fmt.Sprintf("%s:%d", te.Host, port)}
+	opts.Protocol = clickhouse.HTTP
+	opts.AlignServerTimeouts = true
+	opts.ReadTimeout = 42 * time.Second
+	conn, err := GetConnectionWithOptions(&opts)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for name, expected := range map[string]string{
+		"send_timeout":    "42",
+		"receive_timeout": "42",
+	} {
+		var value string
+		require.NoError(t, conn.QueryRow(ctx, "SELECT value FROM system.settings WHERE name = ?", name).Scan(&value))
+		assert.Equal(t, expected, value, name)
+	}
+}
@@ -0,0 +1,75 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowDDL(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, conn.Ping(ctx))
+
+	const ddl = "CREATE TABLE test_allow_ddl (Col1 UInt8) Engine MergeTree() ORDER BY tuple()"
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_allow_ddl")
+	}()
+
+	sandbox := clickhouse.Context(ctx, clickhouse.WithAllowDDL(false))
+	err = conn.Exec(sandbox, ddl)
+	require.Error(t, err)
+	var exception *clickhouse.Exception
+	require.True(t, errors.As(err, &exception))
+	assert.Equal(t, int32(392), exception.Code)
+
+	// queries are still allowed in the restricted context
+	var n uint8
+	require.NoError(t, conn.QueryRow(sandbox, "SELECT 1").Scan(&n))
+	assert.Equal(t, uint8(1), n)
+
+	// the setting applies per query only
+	require.NoError(t, conn.Exec(ctx, ddl))
+}
+
+func TestReadonlyQuery(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, createSimpleTable(conn, "test_readonly_query"))
+	defer dropTable(conn, "test_readonly_query")
+
+	readonly := clickhouse.Context(ctx, clickhouse.WithReadonly(clickhouse.ReadonlyAllowSettings))
+	err = conn.Exec(readonly, "INSERT INTO test_readonly_query VALUES (1)")
+	var exception *clickhouse.Exception
+	require.True(t, errors.As(err, &exception))
+	assert.Equal(t, int32(164), exception.Code)
+
+	require.NoError(t, conn.Exec(ctx, "INSERT INTO test_readonly_query VALUES (1)"))
+}
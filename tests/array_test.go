@@ -373,3 +373,46 @@ func TestSimpleArrayValuer(t *testing.T) {
 	require.NoError(t, rows.Close())
 	require.NoError(t, rows.Err())
 }
+
+func TestFixedSizeGoArray(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	ctx := context.Background()
+	require.NoError(t, err)
+	const ddl = `
+		CREATE TABLE test_array (
+			  Col1 Array(Float32)
+			, Col2 Array(Array(UInt8))
+		) Engine MergeTree() ORDER BY tuple()
+		`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE test_array")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_array")
+	require.NoError(t, err)
+	var (
+		col1Data = [4]float32{0.1, -0.2, 0.3, 1.5}
+		col2Data = [2][3]uint8{{1, 2, 3}, {4, 5, 6}}
+	)
+	require.NoError(t, batch.Append(col1Data, col2Data))
+	require.NoError(t, batch.Send())
+
+	var (
+		col1 [4]float32
+		col2 [2][3]uint8
+	)
+	require.NoError(t, conn.QueryRow(ctx, "SELECT * FROM test_array").Scan(&col1, &col2))
+	assert.Equal(t, col1Data, col1)
+	assert.Equal(t, col2Data, col2)
+
+	// slice targets keep working
+	var col1Slice []float32
+	require.NoError(t, conn.QueryRow(ctx, "SELECT Col1 FROM test_array").Scan(&col1Slice))
+	assert.Equal(t, col1Data[:], col1Slice)
+
+	// length mismatch is an error
+	var short [3]float32
+	require.Error(t, conn.QueryRow(ctx, "SELECT Col1 FROM test_array").Scan(&short))
+}
@@ -0,0 +1,59 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseContext(t *testing.T) {
+	te, err := GetTestEnvironment(testSet)
+	require.NoError(t, err)
+	opts := ClientOptionsFromEnv(te, clickhouse.Settings{})
+	base, shutdown := context.WithCancel(clickhouse.Context(context.Background(), clickhouse.WithQueryID("base-context")))
+	defer shutdown()
+	opts.BaseContext = func() context.Context {
+		return base
+	}
+	conn, err := GetConnectionWithOptions(&opts)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, conn.Ping(ctx))
+	var n uint8
+	require.NoError(t, conn.QueryRow(ctx, "SELECT 1").Scan(&n))
+	assert.Equal(t, uint8(1), n)
+
+	// query options of the base context apply unless the call overrides them
+	var queryID string
+	require.NoError(t, conn.QueryRow(ctx, "SELECT queryID()").Scan(&queryID))
+	assert.Equal(t, "base-context", queryID)
+	require.NoError(t, conn.QueryRow(clickhouse.Context(ctx, clickhouse.WithQueryID("call-context")), "SELECT queryID()").Scan(&queryID))
+	assert.Equal(t, "call-context", queryID)
+
+	shutdown()
+	assert.True(t, errors.Is(conn.Ping(ctx), context.Canceled))
+	assert.True(t, errors.Is(conn.Exec(ctx, "SELECT 1"), context.Canceled))
+	_, err = conn.Query(ctx, "SELECT 1")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
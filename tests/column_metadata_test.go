@@ -0,0 +1,84 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnMetadataFromTable(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	const ddl = `
+		CREATE TABLE test_column_metadata (
+			  Col1 UInt64 COMMENT 'the identifier'
+			, Col2 String
+		) Engine MergeTree() ORDER BY tuple()
+		`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_column_metadata")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+
+	rows, err := conn.Query(ctx, "SELECT Col1, Col2, count() AS total FROM test_column_metadata GROUP BY Col1, Col2")
+	require.NoError(t, err)
+	defer rows.Close()
+	metadata, err := clickhouse.ColumnMetadataFromTable(ctx, conn, rows, "test_column_metadata")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]clickhouse.ColumnMetadata{
+		"Col1":  {DatabaseTypeName: "UInt64", Comment: "the identifier", FromTable: true},
+		"Col2":  {DatabaseTypeName: "String", FromTable: true},
+		"total": {DatabaseTypeName: "UInt64"},
+	}, metadata)
+
+	var database string
+	require.NoError(t, conn.QueryRow(ctx, "SELECT currentDatabase()").Scan(&database))
+	qualified, err := clickhouse.ColumnMetadataFromTable(ctx, conn, rows, database+".test_column_metadata")
+	require.NoError(t, err)
+	assert.Equal(t, metadata, qualified)
+
+	// an expression renamed after a column of another type does not match it
+	expressions, err := conn.Query(ctx, "SELECT toFloat64(Col1) AS Col1 FROM test_column_metadata")
+	require.NoError(t, err)
+	defer expressions.Close()
+	metadata, err = clickhouse.ColumnMetadataFromTable(ctx, conn, expressions, "test_column_metadata")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]clickhouse.ColumnMetadata{
+		"Col1": {DatabaseTypeName: "Float64"},
+	}, metadata)
+
+	// the lookup does not reuse the query id of rs, which is still running
+	queryCtx := clickhouse.Context(ctx, clickhouse.WithQueryID(uuid.NewString()))
+	running, err := conn.Query(queryCtx, "SELECT Col1 FROM test_column_metadata")
+	require.NoError(t, err)
+	defer running.Close()
+	metadata, err = clickhouse.ColumnMetadataFromTable(queryCtx, conn, running, "test_column_metadata")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]clickhouse.ColumnMetadata{
+		"Col1": {DatabaseTypeName: "UInt64", Comment: "the identifier", FromTable: true},
+	}, metadata)
+}
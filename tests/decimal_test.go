@@ -182,6 +182,63 @@ func TestNullableDecimal(t *testing.T) {
 	}
 }
 
+func TestNullableDecimalInterleavedNulls(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	ctx := context.Background()
+	require.NoError(t, err)
+	const ddl = `
+		CREATE TABLE test_decimal (
+			  ID  UInt8
+			, Col1 Nullable(Decimal(18,4))
+		) Engine MergeTree() ORDER BY ID
+		`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_decimal")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+	dec := func(s string) *decimal.Decimal {
+		v := decimal.RequireFromString(s)
+		return &v
+	}
+	values := []*decimal.Decimal{
+		dec("-1.2345"),
+		nil,
+		dec("12.5"),
+		nil,
+		nil,
+		dec("-0.0001"),
+		dec("-99999999999999.9999"),
+		nil,
+	}
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_decimal")
+	require.NoError(t, err)
+	for i, v := range values {
+		require.NoError(t, batch.Append(uint8(i), v))
+	}
+	require.NoError(t, batch.Send())
+	rows, err := conn.Query(ctx, "SELECT * FROM test_decimal ORDER BY ID")
+	require.NoError(t, err)
+	var (
+		id   uint8
+		col1 *decimal.Decimal
+	)
+	for rows.Next() {
+		require.NoError(t, rows.Scan(&id, &col1))
+		expected := values[id]
+		if expected == nil {
+			assert.Nil(t, col1, "row %d", id)
+			continue
+		}
+		require.NotNil(t, col1, "row %d", id)
+		assert.True(t, expected.Equal(*col1), "row %d: expected %s, got %s", id, expected, col1)
+		assert.Equal(t, int32(-4), col1.Exponent())
+	}
+	require.NoError(t, rows.Close())
+	require.NoError(t, rows.Err())
+}
+
 func TestDecimalFlush(t *testing.T) {
 	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
 		Method: clickhouse.CompressionLZ4,
@@ -0,0 +1,73 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck(t *testing.T) {
+	te, err := GetTestEnvironment(testSet)
+	require.NoError(t, err)
+	opts := ClientOptionsFromEnv(te, clickhouse.Settings{})
+	opts.MaxIdleConns = 1
+	opts.HealthCheckInterval = 200 * time.Millisecond
+	var (
+		checks    atomic.Int32
+		unhealthy atomic.Bool
+	)
+	opts.HealthCheck = func(ctx context.Context, conn clickhouse.HealthCheckConn) error {
+		checks.Add(1)
+		var delay uint8
+		if err := conn.QueryRow(ctx, "SELECT 0").Scan(&delay); err != nil {
+			return err
+		}
+		if unhealthy.Load() {
+			return errors.New("replica is lagging")
+		}
+		return nil
+	}
+	conn, err := GetConnectionWithOptions(&opts)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// the first query dials, the idle connection is then checked on its first checkout
+	var n uint8
+	require.NoError(t, conn.QueryRow(ctx, "SELECT 1").Scan(&n))
+	before := checks.Load()
+	require.NoError(t, conn.QueryRow(ctx, "SELECT 1").Scan(&n))
+	assert.Equal(t, before+1, checks.Load())
+
+	// and not again within HealthCheckInterval
+	require.NoError(t, conn.QueryRow(ctx, "SELECT 1").Scan(&n))
+	assert.Equal(t, before+1, checks.Load())
+
+	// an unhealthy connection is replaced by a new one
+	time.Sleep(opts.HealthCheckInterval)
+	unhealthy.Store(true)
+	require.NoError(t, conn.Ping(ctx))
+	assert.Equal(t, before+2, checks.Load())
+}
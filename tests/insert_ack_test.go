@@ -0,0 +1,57 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertAck(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	require.NoError(t, createSimpleTable(conn, "test_insert_ack"))
+	defer dropTable(conn, "test_insert_ack")
+
+	var acks []clickhouse.InsertAck
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithInsertAck(func(ack *clickhouse.InsertAck) {
+		acks = append(acks, *ack)
+	}))
+
+	// synchronous inserts are acknowledged at the end of the query
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_insert_ack")
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, batch.Append(uint8(i)))
+	}
+	require.NoError(t, batch.Send())
+	require.Len(t, acks, 1)
+	assert.Equal(t, uint64(10), acks[0].WrittenRows)
+
+	// asynchronous inserts only when waited for
+	require.NoError(t, conn.AsyncInsert(ctx, "INSERT INTO test_insert_ack VALUES (41)", false))
+	assert.Len(t, acks, 1)
+	require.NoError(t, conn.AsyncInsert(ctx, "INSERT INTO test_insert_ack VALUES (42)", true))
+	assert.Len(t, acks, 2)
+}
@@ -0,0 +1,80 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertSelectProgress(t *testing.T) {
+	te, err := GetTestEnvironment(testSet)
+	require.NoError(t, err)
+	opts := ClientOptionsFromEnv(te, clickhouse.Settings{})
+	// shorter than the query, which must not fail as long as progress is reported
+	opts.ReadTimeout = 2 * time.Second
+	conn, err := GetConnectionWithOptions(&opts)
+	require.NoError(t, err)
+	ctx := context.Background()
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_insert_select")
+	}()
+	require.NoError(t, conn.Exec(ctx, "CREATE TABLE test_insert_select (Col1 UInt64) Engine MergeTree() ORDER BY tuple()"))
+
+	var readRows uint64
+	progressCtx := clickhouse.Context(ctx, clickhouse.WithProgress(func(p *clickhouse.Progress) {
+		readRows += p.Rows
+	}), clickhouse.WithSettings(clickhouse.Settings{
+		"max_block_size": 1,
+	}))
+	require.NoError(t, conn.Exec(progressCtx, "INSERT INTO test_insert_select SELECT number FROM numbers(8) WHERE sleepEachRow(0.5) = 0"))
+	assert.Equal(t, uint64(8), readRows)
+
+	var count uint64
+	require.NoError(t, conn.QueryRow(ctx, "SELECT count() FROM test_insert_select").Scan(&count))
+	assert.Equal(t, uint64(8), count)
+}
+
+func TestInsertSelectCancel(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_insert_select")
+	}()
+	require.NoError(t, conn.Exec(ctx, "CREATE TABLE test_insert_select (Col1 UInt64) Engine Null()"))
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	progressCtx := clickhouse.Context(cancelCtx, clickhouse.WithProgress(func(p *clickhouse.Progress) {
+		cancel()
+	}))
+	start := time.Now()
+	err = conn.Exec(progressCtx, "INSERT INTO test_insert_select SELECT number FROM system.numbers")
+	require.True(t, errors.Is(err, context.Canceled), "%v", err)
+	assert.Less(t, time.Since(start), 30*time.Second)
+
+	// the connection pool recovers from the closed connection
+	require.NoError(t, conn.Ping(ctx))
+}
@@ -143,3 +143,40 @@ func TestNullableArray(t *testing.T) {
 	assert.Equal(t, []*string{&strVal, nil, &strVal}, result.Col13)
 	assert.Equal(t, []*uuid.UUID{&uuidVal, nil, &uuidVal}, result.Col14)
 }
+
+func TestArrayOfNullableRoundTrip(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	// only the elements of an array may be null, the server rejects Nullable(Array(T))
+	require.Error(t, conn.Exec(ctx, "CREATE TEMPORARY TABLE test_nullable_of_array (Col1 Nullable(Array(Int32)))"))
+	const ddl = `
+	CREATE TABLE test_array_of_nullable (
+		  ID   UInt8
+		, Col1 Array(Nullable(Int32))
+	) Engine MergeTree() ORDER BY ID
+	`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_array_of_nullable")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_array_of_nullable")
+	require.NoError(t, err)
+	one, two := int32(1), int32(2)
+	rows := [][]*int32{{&one, nil, &two}, {}, {nil}, {nil, nil}}
+	for i, row := range rows {
+		require.NoError(t, batch.Append(uint8(i), row))
+	}
+	require.NoError(t, batch.Send())
+	result, err := conn.Query(ctx, "SELECT Col1 FROM test_array_of_nullable ORDER BY ID")
+	require.NoError(t, err)
+	defer result.Close()
+	var i int
+	for ; result.Next(); i++ {
+		var col1 []*int32
+		require.NoError(t, result.Scan(&col1))
+		assert.Equal(t, rows[i], col1, "row %d", i)
+	}
+	require.NoError(t, result.Err())
+	assert.Equal(t, len(rows), i)
+}
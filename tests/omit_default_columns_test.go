@@ -0,0 +1,71 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOmitDefaultColumns(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	const ddl = `
+		CREATE TABLE test_omit_default_columns (
+			  Col1 UInt64
+			, Col2 String DEFAULT 'default'
+			, Col3 UInt64 MATERIALIZED Col1 * 2
+			, Col4 DateTime DEFAULT toDateTime('2024-01-01 00:00:00', 'UTC')
+			, Col5 String
+		) Engine MergeTree() ORDER BY tuple()
+		`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_omit_default_columns")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+
+	_, err = conn.PrepareBatch(ctx, "INSERT INTO test_omit_default_columns (Col1)", driver.WithOmitDefaultColumns())
+	var opErr *clickhouse.OpError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, "Col5", opErr.ColumnName)
+
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_omit_default_columns (Col5, Col1)", driver.WithOmitDefaultColumns())
+	require.NoError(t, err)
+	require.NoError(t, batch.Append("a", uint64(21)))
+	require.NoError(t, batch.Send())
+
+	var (
+		col1 uint64
+		col2 string
+		col3 uint64
+		col5 string
+	)
+	require.NoError(t, conn.QueryRow(ctx, "SELECT Col1, Col2, Col3, Col5 FROM test_omit_default_columns").Scan(&col1, &col2, &col3, &col5))
+	assert.Equal(t, uint64(21), col1)
+	assert.Equal(t, "default", col2)
+	assert.Equal(t, uint64(42), col3)
+	assert.Equal(t, "a", col5)
+}
@@ -42,3 +42,18 @@ func TestOpenTelemetry(t *testing.T) {
 	require.NoError(t, rows.Scan(&count))
 	assert.Equal(t, uint64(5), count)
 }
+
+func TestOpenTelemetryActiveSpan(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5},
+		TraceID:    trace.TraceID{5, 4, 3, 2, 1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	var count uint64
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT() FROM (SELECT number FROM system.numbers LIMIT 5)").Scan(&count))
+	assert.Equal(t, uint64(5), count)
+}
@@ -0,0 +1,61 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryScalar(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var count uint64
+	require.NoError(t, clickhouse.QueryScalar(ctx, conn, &count, "SELECT count() FROM numbers(?)", 10))
+	assert.Equal(t, uint64(10), count)
+
+	var exists bool
+	require.NoError(t, clickhouse.QueryScalar(ctx, conn, &exists, "SELECT EXISTS (SELECT 1 FROM system.one)"))
+	assert.True(t, exists)
+
+	var n uint64
+	assert.True(t, errors.Is(clickhouse.QueryScalar(ctx, conn, &n, "SELECT number FROM numbers(1) WHERE number > 1"), sql.ErrNoRows))
+
+	var opErr *clickhouse.OpError
+	require.True(t, errors.As(clickhouse.QueryScalar(ctx, conn, &n, "SELECT number, number FROM numbers(1)"), &opErr))
+	assert.Equal(t, "QueryScalar", opErr.Op)
+	require.True(t, errors.As(clickhouse.QueryScalar(ctx, conn, &n, "SELECT number FROM numbers(2)"), &opErr))
+	assert.Equal(t, "QueryScalar", opErr.Op)
+	// the remaining rows are not read, system.numbers is unbounded
+	require.True(t, errors.As(clickhouse.QueryScalar(ctx, conn, &n, "SELECT number FROM system.numbers"), &opErr))
+	assert.Equal(t, "QueryScalar", opErr.Op)
+
+	// the connection is released on every path
+	require.NoError(t, clickhouse.QueryScalar(ctx, conn, &n, "SELECT toUInt64(42)"))
+	assert.Equal(t, uint64(42), n)
+}
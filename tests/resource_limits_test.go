@@ -0,0 +1,56 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceLimits(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithResourceLimits(clickhouse.ResourceLimits{
+		MaxRowsToRead:    1000,
+		MaxExecutionTime: 10 * time.Second,
+	}))
+
+	var count uint64
+	err = conn.QueryRow(ctx, "SELECT count() FROM numbers(10000)").Scan(&count)
+	var exception *clickhouse.Exception
+	require.True(t, errors.As(err, &exception), "%v", err)
+	assert.Equal(t, int32(158), exception.Code)
+
+	require.NoError(t, conn.QueryRow(ctx, "SELECT count() FROM numbers(100)").Scan(&count))
+	assert.Equal(t, uint64(100), count)
+
+	// invalid limits fail the query instead of running it without them
+	ctx = clickhouse.Context(context.Background(), clickhouse.WithResourceLimits(clickhouse.ResourceLimits{
+		MaxRowsToRead:    1000,
+		MaxExecutionTime: -time.Second,
+	}))
+	assert.Error(t, conn.QueryRow(ctx, "SELECT count() FROM numbers(10000)").Scan(&count))
+}
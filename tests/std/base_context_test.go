@@ -0,0 +1,58 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package std
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	clickhouse_tests "github.com/ClickHouse/clickhouse-go/v2/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdBaseContextPreparedBatch(t *testing.T) {
+	env, err := GetStdTestEnvironment()
+	require.NoError(t, err)
+	opts := clickhouse_tests.ClientOptionsFromEnv(env, clickhouse.Settings{})
+	base, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	opts.BaseContext = func() context.Context {
+		return base
+	}
+	conn := GetConnectionWithOptions(&opts)
+	require.NotNil(t, conn)
+	defer conn.Close()
+	const ddl = "CREATE TABLE test_std_base_context (Col1 UInt8) Engine MergeTree() ORDER BY tuple()"
+	defer func() {
+		conn.Exec("DROP TABLE IF EXISTS test_std_base_context")
+	}()
+	_, err = conn.Exec(ddl)
+	require.NoError(t, err)
+
+	scope, err := conn.Begin()
+	require.NoError(t, err)
+	batch, err := scope.Prepare("INSERT INTO test_std_base_context")
+	require.NoError(t, err)
+	_, err = batch.Exec(uint8(1))
+	require.NoError(t, err)
+	// the prepared batch is cancelled with the base context, even though the call context is not
+	shutdown()
+	assert.Error(t, scope.Commit())
+}
@@ -0,0 +1,172 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package std
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	clickhouse_tests "github.com/ClickHouse/clickhouse-go/v2/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdRowBinaryWithDefaults(t *testing.T) {
+	useSSL, err := strconv.ParseBool(clickhouse_tests.GetEnv("CLICKHOUSE_USE_SSL", "false"))
+	require.NoError(t, err)
+	conn, err := GetStdDSNConnection(clickhouse.HTTP, useSSL, nil)
+	require.NoError(t, err)
+	const ddl = `
+		CREATE TABLE test_row_binary_with_defaults (
+			  Col1 UInt64
+			, Col2 String DEFAULT 'default'
+			, Col3 Array(Nullable(String)) DEFAULT ['a']
+			, Col4 Nullable(UInt8)
+			, Col5 Map(String, UInt8)
+			, Col6 UInt32 MATERIALIZED Col1 * 2
+		) Engine MergeTree() ORDER BY tuple()
+		`
+	conn.Exec("DROP TABLE test_row_binary_with_defaults")
+	defer func() {
+		conn.Exec("DROP TABLE test_row_binary_with_defaults")
+	}()
+	_, err = conn.Exec(ddl)
+	require.NoError(t, err)
+
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithStdRowBinaryWithDefaults())
+	scope, err := conn.Begin()
+	require.NoError(t, err)
+	batch, err := scope.PrepareContext(ctx, "INSERT INTO test_row_binary_with_defaults")
+	require.NoError(t, err)
+	value := "b"
+	_, err = batch.Exec(uint64(1), "value", []*string{&value, nil}, uint8(1), map[string]uint8{"a": 1})
+	require.NoError(t, err)
+	_, err = batch.Exec(uint64(2), nil, nil, nil, map[string]uint8{})
+	require.NoError(t, err)
+	// columns without a DEFAULT expression must be set unless they are Nullable
+	_, err = batch.Exec(uint64(3), nil, nil, nil, nil)
+	require.Error(t, err)
+	require.NoError(t, scope.Rollback())
+
+	scope, err = conn.Begin()
+	require.NoError(t, err)
+	batch, err = scope.PrepareContext(ctx, "INSERT INTO test_row_binary_with_defaults")
+	require.NoError(t, err)
+	_, err = batch.Exec(uint64(1), "value", []*string{&value, nil}, uint8(1), map[string]uint8{"a": 1})
+	require.NoError(t, err)
+	_, err = batch.Exec(uint64(2), nil, nil, nil, map[string]uint8{})
+	require.NoError(t, err)
+	require.NoError(t, scope.Commit())
+
+	rows, err := conn.Query("SELECT Col1, Col2, Col3, Col4, Col5, Col6 FROM test_row_binary_with_defaults ORDER BY Col1")
+	require.NoError(t, err)
+	expected := []struct {
+		col2 string
+		col3 []*string
+		col4 *uint8
+		col5 map[string]uint8
+		col6 uint32
+	}{
+		{"value", []*string{&value, nil}, func() *uint8 { v := uint8(1); return &v }(), map[string]uint8{"a": 1}, 2},
+		{"default", []*string{func() *string { v := "a"; return &v }()}, nil, map[string]uint8{}, 4},
+	}
+	var i int
+	for ; rows.Next(); i++ {
+		var (
+			col1 uint64
+			col2 string
+			col3 []*string
+			col4 *uint8
+			col5 map[string]uint8
+			col6 uint32
+		)
+		require.NoError(t, rows.Scan(&col1, &col2, &col3, &col4, &col5, &col6))
+		assert.Equal(t, uint64(i+1), col1)
+		assert.Equal(t, expected[i].col2, col2)
+		assert.Equal(t, expected[i].col3, col3)
+		assert.Equal(t, expected[i].col4, col4)
+		assert.Equal(t, expected[i].col5, col5)
+		assert.Equal(t, expected[i].col6, col6)
+	}
+	assert.Equal(t, 2, i)
+	require.NoError(t, rows.Close())
+	require.NoError(t, rows.Err())
+}
+
+func TestStdRowBinaryWithDefaultsEnumTuple(t *testing.T) {
+	useSSL, err := strconv.ParseBool(clickhouse_tests.GetEnv("CLICKHOUSE_USE_SSL", "false"))
+	require.NoError(t, err)
+	conn, err := GetStdDSNConnection(clickhouse.HTTP, useSSL, nil)
+	require.NoError(t, err)
+	const ddl = `
+		CREATE TABLE test_row_binary_with_defaults_enum_tuple (
+			  Col1 UInt64
+			, Col2 Enum8('a' = 1, 'b' = 2) DEFAULT 'b'
+			, Col3 Enum16('x' = 10, 'y' = 20) DEFAULT 'y'
+			, Col4 Tuple(UInt8, String) DEFAULT (7, 'seven')
+		) Engine MergeTree() ORDER BY tuple()
+		`
+	conn.Exec("DROP TABLE test_row_binary_with_defaults_enum_tuple")
+	defer func() {
+		conn.Exec("DROP TABLE test_row_binary_with_defaults_enum_tuple")
+	}()
+	_, err = conn.Exec(ddl)
+	require.NoError(t, err)
+
+	ctx := clickhouse.Context(context.Background(), clickhouse.WithStdRowBinaryWithDefaults())
+	scope, err := conn.Begin()
+	require.NoError(t, err)
+	batch, err := scope.PrepareContext(ctx, "INSERT INTO test_row_binary_with_defaults_enum_tuple")
+	require.NoError(t, err)
+	_, err = batch.Exec(uint64(1), "a", "x", []any{uint8(1), "one"})
+	require.NoError(t, err)
+	_, err = batch.Exec(uint64(2), nil, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, scope.Commit())
+
+	rows, err := conn.Query("SELECT Col1, Col2, Col3, tupleElement(Col4, 1), tupleElement(Col4, 2) FROM test_row_binary_with_defaults_enum_tuple ORDER BY Col1")
+	require.NoError(t, err)
+	expected := []struct {
+		col2, col3 string
+		col4       uint8
+		col5       string
+	}{
+		{"a", "x", 1, "one"},
+		{"b", "y", 7, "seven"},
+	}
+	var i int
+	for ; rows.Next(); i++ {
+		var (
+			col1       uint64
+			col2, col3 string
+			col4       uint8
+			col5       string
+		)
+		require.NoError(t, rows.Scan(&col1, &col2, &col3, &col4, &col5))
+		assert.Equal(t, uint64(i+1), col1)
+		assert.Equal(t, expected[i].col2, col2)
+		assert.Equal(t, expected[i].col3, col3)
+		assert.Equal(t, expected[i].col4, col4)
+		assert.Equal(t, expected[i].col5, col5)
+	}
+	assert.Equal(t, 2, i)
+	require.NoError(t, rows.Close())
+	require.NoError(t, rows.Err())
+}
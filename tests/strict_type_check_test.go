@@ -0,0 +1,81 @@
+// Licensed to ClickHouse, Inc. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. ClickHouse, Inc. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictTypeCheck(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	const ddl = `
+		CREATE TABLE test_strict_type_check (
+			  Col1 UInt8
+			, Col2 Nullable(String)
+			, Col3 Enum8('a' = 1, 'b' = 2)
+			, Col4 Array(Int16)
+		) Engine MergeTree() ORDER BY tuple()
+		`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_strict_type_check")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_strict_type_check", driver.WithStrictTypeCheck())
+	require.NoError(t, err)
+	require.NoError(t, batch.Append(1, nil, "a", []int{1, 2}))
+
+	tests := []struct {
+		row    []any
+		column string
+	}{
+		{row: []any{256, nil, "a", []int{}}, column: "Col1"},
+		{row: []any{nil, nil, "a", []int{}}, column: "Col1"},
+		{row: []any{2, nil, "c", []int{}}, column: "Col3"},
+		{row: []any{2, nil, "b", []int{1, 40000}}, column: "Col4"},
+	}
+	for _, test := range tests {
+		err := batch.Append(test.row...)
+		var typeErr *clickhouse.TypeCheckError
+		require.True(t, errors.As(err, &typeErr), "%v", err)
+		assert.Equal(t, 1, typeErr.Row)
+		assert.Equal(t, test.column, typeErr.ColumnName)
+	}
+	// rejected rows are not appended, the batch is still usable
+	require.Equal(t, 1, batch.Rows())
+	require.NoError(t, batch.Append(2, "x", "b", []int{3}))
+	var typeErr *clickhouse.TypeCheckError
+	require.True(t, errors.As(batch.Column(0).Append([]int{3, 300}), &typeErr))
+	assert.Equal(t, 3, typeErr.Row)
+	require.NoError(t, batch.Send())
+
+	var count uint64
+	require.NoError(t, conn.QueryRow(ctx, "SELECT count() FROM test_strict_type_check").Scan(&count))
+	assert.Equal(t, uint64(2), count)
+}
@@ -23,12 +23,15 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
 	"time"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
 )
 
 type testStr struct {
@@ -316,6 +319,74 @@ func TestStringFlush(t *testing.T) {
 	require.Equal(t, 1000, i)
 }
 
+func TestStringByteFidelity(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, &clickhouse.Compression{
+		Method: clickhouse.CompressionLZ4,
+	})
+	ctx := context.Background()
+	require.NoError(t, err)
+	const ddl = `
+		CREATE TABLE test_string_fidelity (
+			  ID   UInt8
+			, Col1 String
+			, Col2 Nullable(String)
+		) Engine MergeTree() ORDER BY ID
+		`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_string_fidelity")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+	values := []string{
+		"trailing spaces   ",
+		"   leading spaces",
+		"embedded\x00null",
+		"trailing nulls\x00\x00",
+		"mixed \x00 \t\n",
+		" ",
+		"",
+	}
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_string_fidelity")
+	require.NoError(t, err)
+	for i, v := range values {
+		require.NoError(t, batch.Append(uint8(i), v, &values[i]))
+	}
+	require.NoError(t, batch.Send())
+
+	rows, err := conn.Query(ctx, "SELECT ID, Col1, Col2, length(Col1) FROM test_string_fidelity ORDER BY ID")
+	require.NoError(t, err)
+	for rows.Next() {
+		var (
+			id     uint8
+			col1   string
+			col2   *string
+			length uint64
+		)
+		require.NoError(t, rows.Scan(&id, &col1, &col2, &length))
+		assert.Equal(t, values[id], col1)
+		require.NotNil(t, col2)
+		assert.Equal(t, values[id], *col2)
+		assert.Equal(t, uint64(len(values[id])), length)
+	}
+	require.NoError(t, rows.Close())
+	require.NoError(t, rows.Err())
+
+	rows, err = conn.Query(ctx, "SELECT ID, Col1, Col2 FROM test_string_fidelity ORDER BY ID")
+	require.NoError(t, err)
+	for rows.Next() {
+		var (
+			id   uint8
+			col1 column.TrimmedString
+			col2 column.TrimmedString
+		)
+		require.NoError(t, rows.Scan(&id, &col1, &col2))
+		expected := column.TrimmedString(strings.TrimRightFunc(values[id], unicode.IsSpace))
+		assert.Equal(t, expected, col1)
+		assert.Equal(t, expected, col2)
+	}
+	require.NoError(t, rows.Close())
+	require.NoError(t, rows.Err())
+}
+
 type testStringSerializer struct {
 	val string
 }
@@ -515,3 +515,38 @@ func TestTupleValuer(t *testing.T) {
 	}
 	require.Equal(t, 1000, i)
 }
+
+func TestArrayOfNamedTupleStructs(t *testing.T) {
+	conn, err := GetNativeConnection(nil, nil, nil)
+	ctx := context.Background()
+	require.NoError(t, err)
+	if !CheckMinServerServerVersion(conn, 22, 5, 0) {
+		t.Skip(fmt.Errorf("unsupported clickhouse version"))
+		return
+	}
+	type pair struct {
+		A uint64
+		B string
+	}
+	const ddl = `
+		CREATE TABLE test_array_tuple_struct (
+			Col1 Array(Tuple(a UInt64, b String))
+		) Engine MergeTree() ORDER BY tuple()
+		`
+	defer func() {
+		conn.Exec(ctx, "DROP TABLE IF EXISTS test_array_tuple_struct")
+	}()
+	require.NoError(t, conn.Exec(ctx, ddl))
+	batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_array_tuple_struct")
+	require.NoError(t, err)
+	col1Data := []pair{{A: 1, B: "x"}, {A: 2, B: "y"}}
+	require.NoError(t, batch.Append(col1Data))
+	require.NoError(t, batch.Send())
+	var col1 []pair
+	require.NoError(t, conn.QueryRow(ctx, "SELECT * FROM test_array_tuple_struct").Scan(&col1))
+	assert.Equal(t, col1Data, col1)
+
+	var grouped []pair
+	require.NoError(t, conn.QueryRow(ctx, "SELECT groupArray((number, toString(number))) FROM (SELECT number FROM system.numbers LIMIT 3)").Scan(&grouped))
+	assert.Equal(t, []pair{{A: 0, B: "0"}, {A: 1, B: "1"}, {A: 2, B: "2"}}, grouped)
+}